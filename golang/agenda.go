@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// agendaDays is the number of days after today listed as upcoming in the agenda.
+const agendaDays = 3
+
+// taskRef is a task together with the name of the project holding it.
+type taskRef struct {
+	project string
+	task    *Task
+}
+
+// agenda lists the open tasks with a deadline: overdue ones first, then the ones due today,
+// then the ones due in the next few days.
+func (l *TaskList) agenda() {
+	today := startOfDay(l.now())
+	upcomingEnd := today.AddDate(0, 0, agendaDays+1)
+
+	var overdue, dueToday, upcoming []taskRef
+	for _, ref := range l.openTasksByDeadline() {
+		due, _ := ref.task.deadline.Date()
+		switch {
+		case due.Before(today):
+			overdue = append(overdue, ref)
+		case due.Before(today.AddDate(0, 0, 1)):
+			dueToday = append(dueToday, ref)
+		case due.Before(upcomingEnd):
+			upcoming = append(upcoming, ref)
+		}
+	}
+
+	if len(overdue)+len(dueToday)+len(upcoming) == 0 {
+		fmt.Fprintln(l.out, "Nothing on the agenda.")
+		return
+	}
+	l.printAgendaSection("Overdue", overdue)
+	l.printAgendaSection("Today", dueToday)
+	l.printAgendaSection(fmt.Sprintf("Next %d days", agendaDays), upcoming)
+	for day := today; day.Before(upcomingEnd); day = day.AddDate(0, 0, 1) {
+		for _, warning := range l.overloadWarnings(day, l.loadOn(day)) {
+			fmt.Fprintln(l.out, warning)
+		}
+	}
+}
+
+// dueWithin lists the open tasks due today or in the next days, earliest first.
+func (l *TaskList) dueWithin(days int) {
+	today := startOfDay(l.now())
+	end := today.AddDate(0, 0, days+1)
+	var refs []taskRef
+	for _, ref := range l.openTasksByDeadline() {
+		if due, _ := ref.task.deadline.Date(); !due.Before(today) && due.Before(end) {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		fmt.Fprintf(l.out, "Nothing due within %s.\n", countOf(days, "day"))
+		return
+	}
+	l.printAgendaSection(fmt.Sprintf("Due within %s", countOf(days, "day")), refs)
+}
+
+// weekDays is the number of days shown by the week view, starting today.
+const weekDays = 7
+
+// week lists the open tasks due on each of the next seven days, one section per day,
+// marking the days with nothing due.
+func (l *TaskList) week() {
+	today := startOfDay(l.now())
+	byDay := map[time.Time][]taskRef{}
+	for _, ref := range l.openTasksByDeadline() {
+		due, _ := ref.task.deadline.Date()
+		byDay[startOfDay(due)] = append(byDay[startOfDay(due)], ref)
+	}
+	for i := 0; i < weekDays; i++ {
+		day := today.AddDate(0, 0, i)
+		title := day.Format("Mon 2006-01-02")
+		if len(byDay[day]) == 0 {
+			fmt.Fprintf(l.out, "%s\n    Nothing due.\n\n", title)
+			continue
+		}
+		l.printAgendaSection(title, byDay[day])
+	}
+}
+
+func (l *TaskList) printAgendaSection(title string, refs []taskRef) {
+	if len(refs) == 0 {
+		return
+	}
+	fmt.Fprintln(l.out, title)
+	table := l.newTaskTable()
+	for _, ref := range refs {
+		table.Add(ref.task, "["+ref.project+"]")
+	}
+	table.Flush(l.out)
+	fmt.Fprintln(l.out)
+}
+
+// openTasksByDeadline returns the unchecked tasks having a date deadline, earliest first.
+func (l *TaskList) openTasksByDeadline() []taskRef {
+	var refs []taskRef
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			if _, ok := task.deadline.Date(); ok && !task.IsDone() {
+				refs = append(refs, taskRef{project: project, task: task})
+			}
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool {
+		a, _ := refs[i].task.deadline.Date()
+		b, _ := refs[j].task.deadline.Date()
+		return a.Before(b)
+	})
+	return refs
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ageMarker = "·"
+
+// parseAgingThresholds reads the ages in working days, e.g. "7,14,30", from which open tasks
+// get one more age marker; "off" disables the markers.
+func parseAgingThresholds(value string) ([]int, error) {
+	if value == "off" {
+		return nil, nil
+	}
+	var thresholds []int
+	for _, field := range strings.Split(value, ",") {
+		days, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || days < 1 || (len(thresholds) > 0 && days <= thresholds[len(thresholds)-1]) {
+			return nil, fmt.Errorf("invalid aging thresholds %q, expected increasing numbers of days or off", value)
+		}
+		thresholds = append(thresholds, days)
+	}
+	return thresholds, nil
+}
+
+func formatAgingThresholds(thresholds []int) string {
+	if len(thresholds) == 0 {
+		return "off"
+	}
+	fields := make([]string, len(thresholds))
+	for i, days := range thresholds {
+		fields[i] = strconv.Itoa(days)
+	}
+	return strings.Join(fields, ",")
+}
+
+// ageInDays returns the number of working days between the creation of the task and today,
+// so weekends and holidays do not make work look stale.
+func ageInDays(task *Task, today time.Time, calendar workCalendar) int {
+	return calendar.BusinessDaysBetween(task.GetCreated(), today)
+}
+
+// renderAge returns one marker per aging threshold reached by an open task.
+func renderAge(task *Task, opts renderOptions) string {
+	if task.IsDone() || task.GetCreated().IsZero() {
+		return ""
+	}
+	age := ageInDays(task, opts.today, opts.workdays)
+	markers := 0
+	for _, threshold := range opts.agingThresholds {
+		if age >= threshold {
+			markers++
+		}
+	}
+	return strings.Repeat(ageMarker, markers)
+}
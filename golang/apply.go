@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const applyUsage = "could not execute apply. Usage: apply [--dry-run] <query> check|uncheck|deadline <date>|context <@context>...|size <S|M|L|none>|tag|untag <label>..."
+
+// apply runs a mutation on every task matching a query, listing the affected tasks.
+// With --dry-run, the tasks are listed but left unchanged. When the mutation fails for
+// one of them, none is changed.
+func (l *TaskList) apply(args []string) error {
+	dryRun := false
+	var rest []string
+	for _, arg := range args {
+		if arg == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	if len(rest) < 2 {
+		return fmt.Errorf(applyUsage)
+	}
+
+	q, err := parseQuery(rest[0], startOfDay(l.now()))
+	if err != nil {
+		l.fail(codeInvalidQuery, "Invalid query \"%s\": %v.", rest[0], err)
+		return nil
+	}
+	mutate, err := l.parseMutation(rest[1], rest[2:])
+	if err != nil {
+		return err
+	}
+	if mutate == nil {
+		return nil
+	}
+
+	refs := l.findTasks(q)
+	if len(refs) == 0 {
+		fmt.Fprintln(l.out, "No task matches the query.")
+		return nil
+	}
+	action := strings.Join(rest[1:], " ")
+	if dryRun {
+		l.printAgendaSection(fmt.Sprintf("Would apply \"%s\" to %s:", action, countOf(len(refs), "task")), refs)
+		return nil
+	}
+	// the tasks are changed as a whole: when checking one is refused, which is reported,
+	// the tasks changed before it are restored
+	var changed []taskRef
+	var refused *Task
+	if !l.atomically(func() bool {
+		for _, ref := range refs {
+			if mutate(ref.task) {
+				changed = append(changed, ref)
+			}
+			if l.usage.failedCode != "" {
+				refused = ref.task
+				return false
+			}
+		}
+		return true
+	}) {
+		fmt.Fprintf(l.out, "Applied \"%s\" to no task, as it failed for task %s.\n", action, refused.GetID())
+		return nil
+	}
+	if len(changed) == 0 {
+		fmt.Fprintf(l.out, "Applied \"%s\" to no task.\n", action)
+		return nil
+	}
+	l.printAgendaSection(fmt.Sprintf("Applied \"%s\" to %s:", action, countOf(len(changed), "task")), changed)
+	return nil
+}
+
+// parseMutation validates an action of apply before any task is changed. The mutation
+// returns whether it changed the task. It returns a nil mutation, without error, when
+// the failure was already reported to the user.
+func (l *TaskList) parseMutation(action string, args []string) (func(task *Task) bool, error) {
+	switch action {
+	case "check", "uncheck":
+		done := action == "check"
+		return func(task *Task) bool {
+			wasDone := task.IsDone()
+			l.complete(task, done)
+			return task.IsDone() != wasDone
+		}, nil
+	case "deadline":
+		if len(args) != 1 {
+			return nil, fmt.Errorf(applyUsage)
+		}
+		d, err := l.parseDeadline(args[0])
+		if err != nil {
+			l.fail(codeInvalidDate, "Invalid date \"%s\".", args[0])
+			return nil, nil
+		}
+		return func(task *Task) bool {
+			previous := task.deadline
+			task.SetDeadline(d)
+			return task.deadline != previous
+		}, nil
+	case "context":
+		contexts := map[string]bool{}
+		for _, word := range args {
+			if !isContext(word) {
+				return nil, fmt.Errorf(applyUsage)
+			}
+			contexts[strings.ToLower(word)] = true
+		}
+		return func(task *Task) bool {
+			if task.contexts == nil {
+				task.contexts = map[string]bool{}
+			}
+			changed := false
+			for context := range contexts {
+				changed = changed || !task.contexts[context]
+				task.contexts[context] = true
+			}
+			return changed
+		}, nil
+	case "size":
+		if len(args) != 1 {
+			return nil, fmt.Errorf(applyUsage)
+		}
+		s, ok := parseSize(args[0])
+		if !ok {
+			return nil, fmt.Errorf(applyUsage)
+		}
+		return func(task *Task) bool {
+			changed := task.size != s
+			task.size = s
+			return changed
+		}, nil
+	case "tag", "untag":
+		tags, ok := parseTags(args)
+		if !ok || len(tags) == 0 {
+			return nil, fmt.Errorf(applyUsage)
+		}
+		add := action == "tag"
+		return func(task *Task) bool {
+			previous := strings.Join(task.GetTags(), " ")
+			task.SetTags(tags, add)
+			return strings.Join(task.GetTags(), " ") != previous
+		}, nil
+	}
+	return nil, fmt.Errorf(applyUsage)
+}
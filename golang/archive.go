@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+const archiveUsage = "could not execute archive. Usage: archive <task ID> | archive done"
+
+// archive moves a checked task, or every checked task with "done", out of the
+// project lists into the archive, where only show archived lists them.
+func (l *TaskList) archive(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf(archiveUsage)
+	}
+	if args[0] == "done" {
+		archived := 0
+		for _, project := range l.sortedProjects() {
+			for _, task := range append([]*Task(nil), l.projectTasks[project]...) {
+				if task.IsDone() {
+					l.archiveTask(task)
+					archived++
+				}
+			}
+		}
+		fmt.Fprintf(l.out, "Archived %s.\n", countOf(archived, "task"))
+		return nil
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	if !task.IsDone() {
+		l.fail(codeTaskNotDone, "Task %s is not done, check it before archiving it.", task.GetID())
+		return nil
+	}
+	l.archiveTask(task)
+	fmt.Fprintf(l.out, "Archived task %s: %s\n", task.GetID(), task.GetDescription())
+	return nil
+}
+
+func (l *TaskList) archiveTask(task *Task) {
+	project := l.removeTask(task)
+	if l.archived == nil {
+		l.archived = map[string][]*Task{}
+	}
+	l.archived[project] = append(l.archived[project], task)
+}
+
+// isArchived returns whether an archived task holds the ID, which stays reserved.
+func (l *TaskList) isArchived(id identifier) bool {
+	for _, tasks := range l.archived {
+		for _, task := range tasks {
+			if task.GetID() == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// showArchived prints the archived tasks, grouped by the project they were in.
+func (l *TaskList) showArchived() {
+	if len(l.archived) == 0 {
+		fmt.Fprintln(l.out, "No archived tasks.")
+		return
+	}
+	projects := make([]string, 0, len(l.archived))
+	for project := range l.archived {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	table := l.newTaskTable()
+	for _, project := range projects {
+		fmt.Fprintln(l.out, project)
+		for _, task := range l.archived[project] {
+			table.Add(task)
+		}
+		table.Flush(l.out)
+		fmt.Fprintln(l.out)
+	}
+}
+
+const clearUsage = "could not execute clear. Usage: clear completed [<project name>] [--archive]"
+
+// clearCompleted deletes the checked tasks of a project, or of every project,
+// or moves them to the archive with --archive.
+func (l *TaskList) clearCompleted(args []string) error {
+	if len(args) < 1 || args[0] != "completed" {
+		return fmt.Errorf(clearUsage)
+	}
+	archive := false
+	var projects []string
+	for _, arg := range args[1:] {
+		switch {
+		case arg == "--archive":
+			archive = true
+		case projects == nil:
+			project, ok := l.findProject(arg)
+			if !ok {
+				l.fail(codeProjectNotFound, "Could not find a project with the name \"%s\".", arg)
+				return nil
+			}
+			projects = []string{project}
+		default:
+			return fmt.Errorf(clearUsage)
+		}
+	}
+	if projects == nil {
+		projects = l.sortedProjects()
+	}
+
+	cleared := 0
+	for _, project := range projects {
+		for _, task := range append([]*Task(nil), l.projectTasks[project]...) {
+			if !task.IsDone() {
+				continue
+			}
+			if archive {
+				l.archiveTask(task)
+			} else {
+				l.recordDeletion(task)
+				l.removeTask(task)
+				l.orphanSubtasks(task)
+				l.forgetBlocker(task)
+			}
+			cleared++
+		}
+	}
+	verb := "Removed"
+	if archive {
+		verb = "Archived"
+	}
+	fmt.Fprintf(l.out, "%s %s.\n", verb, countOf(cleared, "completed task"))
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	assignUsage = "could not execute assign. Usage: assign <task ID> <person>|none"
+	// assigneePrefix introduces an assignee in views, show and queries, e.g. "assignee:alice".
+	assigneePrefix = "assignee:"
+)
+
+// assign names the person owning a task, or leaves it unassigned with "none".
+func (l *TaskList) assign(args []string) error {
+	if len(args) != 2 || !isPersonName(args[1]) {
+		return fmt.Errorf(assignUsage)
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.assignee = args[1]
+	if args[1] == "none" {
+		task.assignee = ""
+	}
+	return nil
+}
+
+// isPersonName returns whether a name is made of letters, digits, dots, dashes and underscores.
+func isPersonName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAssignedTo returns whether the task is owned by the person, ignoring case.
+func (t *Task) IsAssignedTo(person string) bool {
+	return t.assignee != "" && strings.EqualFold(t.assignee, person)
+}
+
+func renderAssignee(task *Task, _ renderOptions) string {
+	if task.assignee == "" {
+		return ""
+	}
+	return assigneePrefix + task.assignee
+}
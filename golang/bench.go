@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	benchUsage = "could not execute bench. Usage: bench [--tasks <count>] [--ops <count>]"
+	// benchProjects is the number of projects the synthetic tasks are spread over.
+	benchProjects = 10
+)
+
+// bench measures the throughput and latency of commands on a synthetic list of tasks,
+// leaving the list of the user untouched.
+func (l *TaskList) bench(args []string) error {
+	tasks, ops := 10000, 1000
+	if len(args)%2 != 0 {
+		return fmt.Errorf(benchUsage)
+	}
+	for i := 0; i < len(args); i += 2 {
+		var target *int
+		switch args[i] {
+		case "--tasks":
+			target = &tasks
+		case "--ops":
+			target = &ops
+		default:
+			return fmt.Errorf(benchUsage)
+		}
+		if err := parseCount(args[i+1], target); err != nil {
+			return fmt.Errorf(benchUsage)
+		}
+	}
+
+	synthetic := NewTaskList(strings.NewReader(""), io.Discard)
+	synthetic.batch = true
+	synthetic.now = l.now
+	start := time.Now()
+	for p := 0; p < benchProjects; p++ {
+		synthetic.addProject(fmt.Sprintf("project-%d", p))
+	}
+	for i := 0; i < tasks; i++ {
+		synthetic.addTask(fmt.Sprintf("project-%d", i%benchProjects), fmt.Sprintf("Synthetic task %d", i+1))
+	}
+	fmt.Fprintf(l.out, "Populated %s in %v.\n", countOf(tasks, "task"), time.Since(start).Round(time.Microsecond))
+	if ops == 0 || tasks == 0 {
+		return nil
+	}
+
+	latencies := make([]time.Duration, ops)
+	start = time.Now()
+	for i := range latencies {
+		cmdLine := benchCommand(i, tasks)
+		began := time.Now()
+		if err := synthetic.execute(cmdLine); err != nil {
+			return err
+		}
+		latencies[i] = time.Since(began)
+	}
+	elapsed := time.Since(start)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	fmt.Fprintf(l.out, "Ran %s in %v (%.0f ops/s).\n", countOf(ops, "command"), elapsed.Round(time.Microsecond), float64(ops)/elapsed.Seconds())
+	fmt.Fprintf(l.out, "Latency: p50 %v, p90 %v, p99 %v, max %v.\n",
+		percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99), latencies[len(latencies)-1])
+	return nil
+}
+
+// benchCommand returns the i-th command of the benchmark, cycling through lookups by ID.
+func benchCommand(i, tasks int) string {
+	id := i%tasks + 1
+	switch i % 4 {
+	case 0:
+		return fmt.Sprintf("check %d", id)
+	case 1:
+		return fmt.Sprintf("deadline %d 20240612", id)
+	case 2:
+		return fmt.Sprintf("context %d @bench", id)
+	default:
+		return fmt.Sprintf("uncheck %d", id)
+	}
+}
+
+// percentile returns the p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	return sorted[(len(sorted)-1)*p/100]
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// brief writes a short spoken-style summary of the tasks due today, the overdue ones
+// and the upcoming ones, suitable for text-to-speech.
+func (l *TaskList) brief() {
+	today := startOfDay(l.now())
+	upcomingEnd := today.AddDate(0, 0, agendaDays+1)
+
+	var dueToday, overdue, upcoming []taskRef
+	for _, ref := range l.openTasksByDeadline() {
+		due, _ := ref.task.deadline.Date()
+		switch {
+		case due.Before(today):
+			overdue = append(overdue, ref)
+		case ref.task.IsDueOn(today):
+			dueToday = append(dueToday, ref)
+		case due.Before(upcomingEnd):
+			upcoming = append(upcoming, ref)
+		}
+	}
+
+	sentences := []string{}
+	if len(dueToday) == 0 {
+		sentences = append(sentences, "You have nothing due today.")
+	} else {
+		sentences = append(sentences, fmt.Sprintf("You have %s due today, %s.",
+			countOf(len(dueToday), "task"), inProjects(dueToday)))
+	}
+	if len(overdue) > 0 {
+		sentences = append(sentences, fmt.Sprintf("%s overdue, %s.",
+			capitalize(countOf(len(overdue), "task")), inProjects(overdue)))
+	}
+	if len(upcoming) > 0 {
+		sentences = append(sentences, fmt.Sprintf("%s due in the next %d days.",
+			capitalize(countOf(len(upcoming), "more task")), agendaDays))
+	}
+	fmt.Fprintln(l.out, strings.Join(sentences, " "))
+}
+
+// countOf returns the number of things, spelled with the plural form when needed.
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// inProjects names the distinct projects holding the tasks, e.g. "in projects home and work".
+func inProjects(refs []taskRef) string {
+	var projects []string
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if !seen[ref.project] {
+			seen[ref.project] = true
+			projects = append(projects, ref.project)
+		}
+	}
+	if len(projects) == 1 {
+		return "in project " + projects[0]
+	}
+	return "in projects " + strings.Join(projects[:len(projects)-1], ", ") + " and " + projects[len(projects)-1]
+}
+
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	monthLayout  = "200601"
+	calendarCell = "%2d%-4s"
+)
+
+var weekdayHeaders = []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}
+
+// calendar renders a month grid with the number of open tasks due on each day.
+// Without arguments, the current month is shown. It backs both calendar and view calendar.
+func (l *TaskList) calendar(args []string) error {
+	month := l.now()
+	if len(args) > 0 {
+		parsed, err := time.ParseInLocation(monthLayout, args[0], time.Local)
+		if err != nil {
+			return fmt.Errorf("could not execute calendar. Usage: calendar [<YYYYMM>] | view calendar [<YYYYMM>]")
+		}
+		month = parsed
+	}
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	fmt.Fprintf(l.out, "%s %d\n", first.Month(), first.Year())
+	headers := make([]string, len(weekdayHeaders))
+	for i, header := range weekdayHeaders {
+		headers[i] = fmt.Sprintf("%-6s", header)
+	}
+	fmt.Fprintln(l.out, strings.TrimRight(strings.Join(headers, " "), " "))
+
+	cells := make([]string, 0, 7)
+	for i := 0; i < mondayOffset(first.Weekday()); i++ {
+		cells = append(cells, strings.Repeat(" ", 6))
+	}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		count := ""
+		if due := l.countOpenDueOn(day); due > 0 {
+			count = fmt.Sprintf("[%d]", due)
+		}
+		cells = append(cells, fmt.Sprintf(calendarCell, day.Day(), count))
+		if len(cells) == 7 {
+			fmt.Fprintln(l.out, strings.TrimRight(strings.Join(cells, " "), " "))
+			cells = cells[:0]
+		}
+	}
+	if len(cells) > 0 {
+		fmt.Fprintln(l.out, strings.TrimRight(strings.Join(cells, " "), " "))
+	}
+	fmt.Fprintln(l.out)
+	return nil
+}
+
+func (l *TaskList) countOpenDueOn(day time.Time) int {
+	count := 0
+	for _, tasks := range l.projectTasks {
+		for _, task := range tasks {
+			if !task.IsDone() && task.IsDueOn(day) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// mondayOffset returns the number of days between the Monday starting the week and the given weekday.
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// commandHandler runs a command with the words typed after its name.
+type commandHandler func(l *TaskList, args []string) error
+
+// commands maps the name of each flat command to its handler. It is shared by runCommand
+// and the suggestions for mistyped commands, so a new command only needs an entry here.
+// It is filled in init, as handlers such as replay run commands in turn.
+var commands map[string]commandHandler
+
+func init() {
+	commands = map[string]commandHandler{
+		"show": func(l *TaskList, args []string) error { return l.show(args) },
+		"add": func(l *TaskList, args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("could not execute add, it requires at least 2 parameters")
+			}
+			return l.add(args)
+		},
+		"check":   func(l *TaskList, args []string) error { return l.setDoneCommand("check", args) },
+		"uncheck": func(l *TaskList, args []string) error { return l.setDoneCommand("uncheck", args) },
+		"delete":  func(l *TaskList, args []string) error { return l.delete(args) },
+		"rename": func(l *TaskList, args []string) error {
+			if len(args) != 3 || args[0] != "project" {
+				return fmt.Errorf("could not execute rename. Usage: rename project <old name> <new name>")
+			}
+			l.renameProject(args[1], args[2])
+			return nil
+		},
+		"move": func(l *TaskList, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("could not execute move. Usage: move <task ID> <project name>")
+			}
+			l.move(args[0], args[1])
+			return nil
+		},
+		"note": func(l *TaskList, args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("could not execute note. Usage: note <task ID> <text>")
+			}
+			l.note(args[0], strings.Join(args[1:], " "))
+			return nil
+		},
+		"edit": func(l *TaskList, args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("could not execute edit. Usage: edit <task ID> <new description>")
+			}
+			l.edit(args[0], strings.Join(args[1:], " "))
+			return nil
+		},
+		"help":     withoutArgs((*TaskList).help),
+		"tutorial": withoutArgs((*TaskList).tutorial),
+		"deadline": func(l *TaskList, args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("could not execute deadline. Usage: deadline <taskId> <dateAsString>")
+			}
+			l.deadline(args[0], args[1])
+			return nil
+		},
+		"depends": func(l *TaskList, args []string) error { return l.depends(args) },
+		"repeat":  func(l *TaskList, args []string) error { return l.repeat(args) },
+		"snooze": func(l *TaskList, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf(snoozeUsage)
+			}
+			return l.snooze(args[0], args[1])
+		},
+		"today":    withoutArgs((*TaskList).today),
+		"agenda":   withoutArgs((*TaskList).agenda),
+		"calendar": func(l *TaskList, args []string) error { return l.calendar(args) },
+		"week":     withoutArgs((*TaskList).week),
+		"due": func(l *TaskList, args []string) error {
+			days, err := strconv.Atoi(strings.Join(args, " "))
+			if err != nil || days < 0 {
+				return fmt.Errorf("could not execute due. Usage: due <number of days>")
+			}
+			l.dueWithin(days)
+			return nil
+		},
+		"brief":     withoutArgs((*TaskList).brief),
+		"rebalance": withoutArgs((*TaskList).rebalance),
+		"rollover":  withoutArgs((*TaskList).rolloverCommand),
+		"yesterday": withoutArgs((*TaskList).yesterday),
+		"context":   func(l *TaskList, args []string) error { return l.setContexts(args) },
+		"size":      func(l *TaskList, args []string) error { return l.setSize(args) },
+		"priority":  func(l *TaskList, args []string) error { return l.setPriority(args) },
+		"tag":       func(l *TaskList, args []string) error { return l.tag(args, true) },
+		"untag":     func(l *TaskList, args []string) error { return l.tag(args, false) },
+		"search": func(l *TaskList, args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("could not execute search. Usage: search <text>")
+			}
+			l.search(strings.Join(args, " "))
+			return nil
+		},
+		"replace": func(l *TaskList, args []string) error { return l.replaceText(args) },
+		"apply":   func(l *TaskList, args []string) error { return l.apply(args) },
+		"goal":    func(l *TaskList, args []string) error { return l.goal(args) },
+		"goals":   withoutArgs((*TaskList).showGoals),
+		"habit":   func(l *TaskList, args []string) error { return l.habit(args) },
+		"habits":  withoutArgs((*TaskList).showHabits),
+		"view":    func(l *TaskList, args []string) error { return l.view(args) },
+		"import": func(l *TaskList, args []string) error {
+			if len(args) != 3 || (args[0] != "email" && args[0] != "csv") {
+				return fmt.Errorf("could not execute import. Usage: import email <project name> <file|-> | import csv <mapping file> <file|->")
+			}
+			if args[0] == "csv" {
+				l.importCSV(args[1], args[2])
+				return nil
+			}
+			l.importEmail(args[1], args[2])
+			return nil
+		},
+		"export": func(l *TaskList, args []string) error { return l.export(args) },
+		"set": func(l *TaskList, args []string) error {
+			l.set(args)
+			return nil
+		},
+		"record": func(l *TaskList, args []string) error { return l.record(args) },
+		"replay": func(l *TaskList, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf(replayUsage)
+			}
+			l.replay(args[0])
+			return nil
+		},
+		"start": func(l *TaskList, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("could not execute start. Usage: start <task ID>")
+			}
+			l.startTracking(args[0])
+			return nil
+		},
+		"stop":      withoutArgs((*TaskList).stopTracking),
+		"progress":  func(l *TaskList, args []string) error { return l.setTaskProgress(args) },
+		"estimate":  func(l *TaskList, args []string) error { return l.estimate(args) },
+		"assign":    func(l *TaskList, args []string) error { return l.assign(args) },
+		"report":    func(l *TaskList, args []string) error { return l.report(args) },
+		"pomodoro":  func(l *TaskList, args []string) error { return l.pomodoro(args) },
+		"remind":    func(l *TaskList, args []string) error { return l.remind(args) },
+		"notify":    func(l *TaskList, args []string) error { return l.notifyTest(args) },
+		"clear":     func(l *TaskList, args []string) error { return l.clearCompleted(args) },
+		"archive":   func(l *TaskList, args []string) error { return l.archive(args) },
+		"undo":      withoutArgs((*TaskList).undo),
+		"redo":      withoutArgs((*TaskList).redo),
+		"telemetry": func(l *TaskList, args []string) error { return l.telemetry(args) },
+		"bench":     func(l *TaskList, args []string) error { return l.bench(args) },
+		"doctor":    withoutArgs((*TaskList).doctor),
+	}
+}
+
+// withoutArgs adapts a command taking no argument, ignoring any typed after its name.
+func withoutArgs(run func(l *TaskList)) commandHandler {
+	return func(l *TaskList, _ []string) error {
+		run(l)
+		return nil
+	}
+}
+
+// setDoneCommand checks or unchecks the tasks with the given IDs.
+func (l *TaskList) setDoneCommand(command string, ids []string) error {
+	if len(ids) < 1 {
+		return fmt.Errorf("could not execute %s. Usage: %s <task ID> [<task ID>...]", command, command)
+	}
+	l.setDoneAll(ids, command == "check")
+	return nil
+}
+
+// commandNames returns every command a user can type: the flat commands, the groups of
+// the grouped grammar and Quit, sorted alphabetically.
+func commandNames() []string {
+	names := []string{"task", "project", Quit}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configEnv names the environment variable overriding the configuration file location.
+const configEnv = "TASK_LIST_CONFIG"
+
+// configPath returns the location of the configuration file: $TASK_LIST_CONFIG when set,
+// ~/.task-list.conf otherwise.
+func configPath() string {
+	if path := os.Getenv(configEnv); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".task-list.conf")
+}
+
+// LoadConfig applies the settings read from a configuration file.
+// Each line holds a "key = value" pair, values may be double-quoted to keep
+// surrounding spaces, and lines starting with # are comments.
+func (l *TaskList) LoadConfig(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("config line %d: expected key = value", lineNumber)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("config line %d: invalid quoted value %s", lineNumber, value)
+			}
+			value = unquoted
+		}
+		if err := l.settings.Set(key, value); err != nil {
+			return fmt.Errorf("config line %d: %v", lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// loadConfigFile applies the configuration file at path, if it exists.
+func (l *TaskList) loadConfigFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return l.LoadConfig(file)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const contextUsage = "could not execute context. Usage: context <task ID> <@context>... | context <task ID> none"
+
+// isContext returns whether the word names a context, such as @home or @errands.
+func isContext(word string) bool {
+	return len(word) > 1 && strings.HasPrefix(word, "@") && !strings.ContainsAny(word[1:], "@ ")
+}
+
+// setContexts replaces the contexts a task can be done in, "none" clearing them.
+func (l *TaskList) setContexts(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf(contextUsage)
+	}
+	contexts := map[string]bool{}
+	if !(len(args) == 2 && args[1] == "none") {
+		for _, word := range args[1:] {
+			if !isContext(word) {
+				return fmt.Errorf(contextUsage)
+			}
+			contexts[strings.ToLower(word)] = true
+		}
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.contexts = contexts
+	return nil
+}
+
+// HasContext returns whether the task can be done in the given context.
+func (t *Task) HasContext(context string) bool {
+	return t.contexts[strings.ToLower(context)]
+}
+
+// GetContexts returns the contexts of the task, sorted alphabetically.
+func (t *Task) GetContexts() []string {
+	contexts := make([]string, 0, len(t.contexts))
+	for context := range t.contexts {
+		contexts = append(contexts, context)
+	}
+	sort.Strings(contexts)
+	return contexts
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// crashError is returned by a command that panicked.
+type crashError struct {
+	message string
+}
+
+func (e crashError) Error() string {
+	return e.message
+}
+
+// recoverCrash keeps the session alive when the command being run panics: the stack trace
+// is appended to the crash log, and the command returns an error telling it failed.
+func (l *TaskList) recoverCrash(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if writeErr := l.writeCrash(r, debug.Stack()); writeErr != nil {
+		*err = crashError{fmt.Sprintf("Command \"%s\" failed unexpectedly: %v.", l.command, r)}
+		return
+	}
+	*err = crashError{fmt.Sprintf("Command \"%s\" failed unexpectedly, details were written to %s.", l.command, l.crashLog)}
+}
+
+func (l *TaskList) writeCrash(cause interface{}, stack []byte) error {
+	file, err := os.OpenFile(l.crashLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "%s %q: %v\n%s\n", time.Now().Format(time.RFC3339), l.command, cause, stack)
+	return err
+}
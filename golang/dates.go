@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// parseDeadline reads a deadline as the user writes it: a date NewDeadline accepts,
+// an offset such as "+3bd", or words such as "tomorrow", counted from the session clock.
+func (l *TaskList) parseDeadline(value string) (deadline, error) {
+	return NewDeadline(l.settings.workdays.resolveDeadline(value, l.now()))
+}
+
+// parseNaturalDate reads the day a deadline names in words, counted from today:
+// "today", "tomorrow", a weekday such as "friday" or "fri" for its next occurrence,
+// "next week" for next Monday and "next month" for the first day of next month.
+func parseNaturalDate(value string, today time.Time) (time.Time, bool) {
+	today = startOfDay(today)
+	words := strings.Fields(strings.ToLower(value))
+	switch strings.Join(words, " ") {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "next week":
+		return today.AddDate(0, 0, 7-mondayOffset(today.Weekday())), true
+	case "next month":
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+	}
+	if len(words) != 1 {
+		return time.Time{}, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if words[0] == name || words[0] == name[:3] {
+			ahead := (int(day) - int(today.Weekday()) + 7) % 7
+			if ahead == 0 {
+				ahead = 7
+			}
+			return today.AddDate(0, 0, ahead), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// demoDate is the moment the clock stays at in demo mode, so every session shows the same list.
+var demoDate = time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+
+// demoCommands seed the list in demo mode when no fixtures file is given.
+const demoCommands = `add project workshop
+add task workshop Book the room
+add task workshop Send the invitations
+add task workshop Print the handouts
+deadline 1 20240607
+deadline 2 20240610
+deadline 3 20240612
+check 1
+context 3 @office
+size 3 M
+add project home
+add task home Renew the insurance
+add task home Fix the bike
+deadline 4 20240614
+context 5 @garage
+size 5 S
+goal add Ready for the workshop --by 2024-06-14
+goal link 2 1
+goal link 3 1
+habit add Stretch daily
+`
+
+// SeedDemo freezes the clock at demoDate and runs the commands of the fixtures file,
+// one per line with # comments, or the built-in sample list when fixtures is empty.
+func (l *TaskList) SeedDemo(fixtures string) error {
+	l.now = func() time.Time { return demoDate }
+	if fixtures == "" {
+		return l.seed(strings.NewReader(demoCommands))
+	}
+	file, err := os.Open(fixtures)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return l.seed(file)
+}
+
+// seed runs commands without showing their confirmations, only failing commands stop it.
+func (l *TaskList) seed(in io.Reader) error {
+	out := l.out
+	l.out = io.Discard
+	defer func() { l.out = out }()
+
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := l.execute(line); err != nil {
+			return fmt.Errorf("fixtures line %d: %v", lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const dependsUsage = "could not execute depends. Usage: depends <task ID> on <task ID> | depends <task ID> none"
+
+func (l *TaskList) depends(args []string) error {
+	if !(len(args) == 3 && args[1] == "on") && !(len(args) == 2 && args[1] == "none") {
+		return fmt.Errorf(dependsUsage)
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	if args[1] == "none" {
+		task.blockers = nil
+		return nil
+	}
+	blocker, err := l.getTaskBy(args[2])
+	if err != nil {
+		return nil
+	}
+	if blocker == task || blocker.dependsOn(task) {
+		l.fail(codeDependencyCycle, "Task %s cannot depend on task %s, which waits for it.", task.GetID(), blocker.GetID())
+		return nil
+	}
+	if !task.dependsOn(blocker) {
+		task.blockers = append(task.blockers, blocker)
+	}
+	return nil
+}
+
+// dependsOn returns whether the task waits for other, directly or through its blockers.
+func (t *Task) dependsOn(other *Task) bool {
+	for _, blocker := range t.blockers {
+		if blocker == other || blocker.dependsOn(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenBlockers returns the unchecked tasks this task waits for.
+func (t *Task) OpenBlockers() []*Task {
+	var open []*Task
+	for _, blocker := range t.blockers {
+		if !blocker.IsDone() {
+			open = append(open, blocker)
+		}
+	}
+	return open
+}
+
+// renderBlocked flags an open task still waiting for other tasks, e.g. "blocked by 3, 5".
+func renderBlocked(task *Task, _ renderOptions) string {
+	if task.IsDone() {
+		return ""
+	}
+	return blockedBy(task)
+}
+
+func blockedBy(task *Task) string {
+	open := task.OpenBlockers()
+	if len(open) == 0 {
+		return ""
+	}
+	ids := make([]string, len(open))
+	for i, blocker := range open {
+		ids[i] = string(blocker.GetID())
+	}
+	return "blocked by " + strings.Join(ids, ", ")
+}
+
+// forgetBlocker removes a deleted task from the dependencies of the others.
+func (l *TaskList) forgetBlocker(deleted *Task) {
+	for _, tasks := range l.projectTasks {
+		for _, task := range tasks {
+			for i, blocker := range task.blockers {
+				if blocker == deleted {
+					task.blockers = append(task.blockers[:i], task.blockers[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// doctorCheck is the outcome of one check of the environment, with a fix when it failed.
+type doctorCheck struct {
+	name   string
+	detail string
+	fix    string
+}
+
+// doctor checks the environment the task list runs in and suggests how to fix each problem.
+func (l *TaskList) doctor() {
+	checks := []doctorCheck{l.checkConfig(), l.checkClock(), l.checkCrashLog()}
+	failures := 0
+	for _, check := range checks {
+		if check.fix == "" {
+			fmt.Fprintf(l.out, "ok    %s: %s\n", check.name, check.detail)
+			continue
+		}
+		failures++
+		fmt.Fprintf(l.out, "FAIL  %s: %s\n      fix: %s\n", check.name, check.detail, check.fix)
+	}
+	if failures == 0 {
+		fmt.Fprintln(l.out, "Everything looks fine.")
+		return
+	}
+	fmt.Fprintf(l.out, "%s found.\n", capitalize(countOf(failures, "problem")))
+}
+
+// checkConfig parses the configuration file into fresh settings, leaving the current ones alone.
+func (l *TaskList) checkConfig() doctorCheck {
+	path := configPath()
+	if path == "" {
+		return doctorCheck{name: "config", detail: "no home directory to look for a configuration file", fix: "set " + configEnv + " to the path of a configuration file"}
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return doctorCheck{name: "config", detail: fmt.Sprintf("no file at %s, defaults are used", path)}
+	}
+	if err != nil {
+		return doctorCheck{name: "config", detail: err.Error(), fix: "make " + path + " readable"}
+	}
+	defer file.Close()
+	probe := &TaskList{settings: defaultSettings()}
+	if err := probe.LoadConfig(file); err != nil {
+		return doctorCheck{name: "config", detail: fmt.Sprintf("%s: %v", path, err), fix: "correct the line in " + path + ", or list valid keys with set"}
+	}
+	return doctorCheck{name: "config", detail: path + " parses"}
+}
+
+// checkClock looks for a clock set to an implausible date.
+func (l *TaskList) checkClock() doctorCheck {
+	now := l.now()
+	zone, _ := now.Zone()
+	detail := fmt.Sprintf("%s (%s)", now.Format("2006-01-02 15:04"), zone)
+	if now.Year() < 2000 || now.Year() > 2100 {
+		return doctorCheck{name: "clock", detail: detail, fix: "set the system date and time, deadlines and ages depend on it"}
+	}
+	return doctorCheck{name: "clock", detail: detail}
+}
+
+// checkCrashLog makes sure the details of failing commands can be kept.
+func (l *TaskList) checkCrashLog() doctorCheck {
+	file, err := os.OpenFile(l.crashLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return doctorCheck{name: "crash log", detail: err.Error(), fix: "make " + filepath.Dir(l.crashLog) + " writable, or set TMPDIR to a writable directory"}
+	}
+	file.Close()
+	return doctorCheck{name: "crash log", detail: l.crashLog + " is writable"}
+}
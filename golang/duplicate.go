@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// minTypoLength is the length under which descriptions must be equal to be duplicates,
+// as a typo in a short one, e.g. "Buy milk" and "Buy silk", is as likely another task.
+const minTypoLength = 12
+
+// findDuplicate returns the open task of the project whose description closely matches
+// the given one: equal once normalized, or, when long enough, within a few typos of it
+// and holding the same numbers, so "Fix bug 12" and "Fix bug 13" stay distinct.
+func (l *TaskList) findDuplicate(projectName, description string) (*Task, bool) {
+	normalized := normalizeForComparison(description)
+	if normalized == "" {
+		return nil, false
+	}
+	projectName, _ = l.findProject(projectName)
+	for _, task := range l.projectTasks[projectName] {
+		if task.IsDone() {
+			continue
+		}
+		other := normalizeForComparison(task.GetDescription())
+		if other == normalized {
+			return task, true
+		}
+		shortest, longest := len([]rune(normalized)), len([]rune(other))
+		if shortest > longest {
+			shortest, longest = longest, shortest
+		}
+		if shortest < minTypoLength || digitsOf(normalized) != digitsOf(other) {
+			continue
+		}
+		if editDistance(normalized, other) <= longest/5 {
+			return task, true
+		}
+	}
+	return nil, false
+}
+
+// digitsOf returns the digits of text, in order.
+func digitsOf(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, text)
+}
+
+// normalizeForComparison lowercases text, drops punctuation and collapses white space.
+func normalizeForComparison(text string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+// editDistance returns the Levenshtein distance between two strings, counted in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// confirm asks the user a yes or no question, reading the answer from the input.
+// In batch mode nobody is there to answer, so the question is declined.
+func (l *TaskList) confirm(question string) bool {
+	if l.batch {
+		fmt.Fprintf(l.out, "%s [y/N] n (batch mode)\n", question)
+		return false
+	}
+	fmt.Fprintf(l.out, "%s [y/N] ", question)
+	scanner := l.input()
+	if !scanner.Scan() {
+		fmt.Fprintln(l.out)
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+)
+
+// dueToken prefixes the deadline written in the body of an email, e.g. "due:20240601".
+const dueToken = "due:"
+
+// importEmail creates a task in the given project from an email message stored in a file,
+// or read from the standard input when the path is "-":
+// the subject becomes the description, and a due:<date> token in the body sets the deadline.
+// When the inbox setting holds an address, messages not sent to it are rejected.
+func (l *TaskList) importEmail(projectName, path string) {
+	file, err := l.openInput(path)
+	if err != nil {
+		l.fail(codeImportFailed, "Could not read email: %v.", err)
+		return
+	}
+	defer file.Close()
+
+	msg, err := mail.ReadMessage(file)
+	if err != nil {
+		l.fail(codeImportFailed, "Could not read email: %v.", err)
+		return
+	}
+	if l.settings.inbox != "" && !isAddressedTo(msg.Header, l.settings.inbox) {
+		l.fail(codeImportFailed, "Email is not addressed to %s.", l.settings.inbox)
+		return
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || strings.TrimSpace(subject) == "" {
+		l.fail(codeImportFailed, "Could not import email without a subject.")
+		return
+	}
+	body, err := plainTextBody(msg)
+	if err != nil {
+		l.fail(codeImportFailed, "Could not read email: %v.", err)
+		return
+	}
+
+	task := l.addTask(projectName, l.settings.descriptions.Apply(strings.TrimSpace(subject)))
+	if task == nil {
+		return
+	}
+	if due, ok := findDueToken(body); ok {
+		if deadline, err := l.parseDeadline(due); err == nil {
+			task.SetDeadline(deadline)
+		}
+	}
+	fmt.Fprintf(l.out, "Imported task %s: %s\n", task.GetID(), task.GetDescription())
+}
+
+func isAddressedTo(header mail.Header, address string) bool {
+	for _, field := range []string{"To", "Cc", "Delivered-To"} {
+		recipients, err := header.AddressList(field)
+		if err != nil {
+			continue
+		}
+		for _, recipient := range recipients {
+			if strings.EqualFold(recipient.Address, address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// plainTextBody returns the text of a message, taking the first text/plain part of multipart messages.
+func plainTextBody(msg *mail.Message) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		body, err := io.ReadAll(msg.Body)
+		return string(body), err
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain") {
+			body, err := io.ReadAll(part)
+			return string(body), err
+		}
+	}
+}
+
+func findDueToken(body string) (string, bool) {
+	for _, word := range strings.Fields(body) {
+		if strings.HasPrefix(strings.ToLower(word), dueToken) {
+			return word[len(dueToken):], true
+		}
+	}
+	return "", false
+}
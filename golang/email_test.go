@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeEmail(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "message.eml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportEmail(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project inbox", "set inbox tasks@example.com")
+	path := writeEmail(t, "From: Ada <ada@example.com>\r\n"+
+		"To: Tasks <tasks@example.com>\r\n"+
+		"Subject: =?UTF-8?Q?R=C3=A9server_la_salle?=\r\n"+
+		"Content-Type: multipart/alternative; boundary=sep\r\n"+
+		"\r\n"+
+		"--sep\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n"+
+		"\r\n"+
+		"For the workshop, due:20240612 please.\r\n"+
+		"--sep\r\n"+
+		"Content-Type: text/html\r\n"+
+		"\r\n"+
+		"<p>due:20991231</p>\r\n"+
+		"--sep--\r\n")
+	out.Reset()
+
+	executeAll(t, l, "import email inbox "+path, "show")
+	assertOutput(t, out,
+		"Imported task 1: Réserver la salle",
+		"inbox (0/1, 0%)",
+		"    [ ] 1: (20240612) Réserver la salle",
+		"",
+	)
+}
+
+func TestImportEmailRejectsOtherRecipients(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project inbox", "set inbox tasks@example.com")
+	path := writeEmail(t, "To: someone@example.com\r\nSubject: Spam\r\n\r\nBuy now\r\n")
+	out.Reset()
+
+	executeAll(t, l, "import email inbox "+path)
+	assertOutput(t, out, "Email is not addressed to tasks@example.com.")
+}
+
+func TestImportEmailFromInput(t *testing.T) {
+	in := strings.NewReader("To: tasks@example.com\r\nSubject: Call back\r\n\r\ndue:20240611\r\n")
+	var out bytes.Buffer
+	l := NewTaskList(in, &out)
+	l.now = func() time.Time { return time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local) }
+	executeAll(t, l, "add project inbox")
+	out.Reset()
+
+	if err := l.RunArgs([]string{"import", "email", "inbox", "-"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertOutput(t, &out, "Imported task 1: Call back")
+}
+
+func TestImportEmailWithDueInWords(t *testing.T) {
+	in := strings.NewReader("To: tasks@example.com\r\nSubject: Call back\r\n\r\nBefore due:tomorrow please.\r\n")
+	var out bytes.Buffer
+	l := NewTaskList(in, &out)
+	l.now = func() time.Time { return time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local) }
+	executeAll(t, l, "add project inbox")
+	out.Reset()
+
+	if err := l.RunArgs([]string{"import", "email", "inbox", "-"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	executeAll(t, l, "show")
+	assertOutput(t, &out,
+		"Imported task 1: Call back",
+		"inbox (0/1, 0%)",
+		"    [ ] 1: (20240611) Call back",
+		"",
+	)
+}
+
+func TestImportEmailAfterCommandsOnInput(t *testing.T) {
+	in := strings.NewReader("add project inbox\nimport email inbox -\nTo: tasks@example.com\r\nSubject: Call back\r\n\r\ndue:20240611\r\n")
+	var out bytes.Buffer
+	l := NewTaskList(in, &out)
+	l.now = func() time.Time { return time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local) }
+
+	if err := l.RunBatch(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	executeAll(t, l, "show")
+	assertOutput(t, &out,
+		"Imported task 1: Call back",
+		"inbox (0/1, 0%)",
+		"    [ ] 1: (20240611) Call back",
+		"",
+	)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+)
+
+// EndSession closes a session with a summary of the day, when the quit-summary setting is on.
+func (l *TaskList) EndSession() {
+	if l.settings.quitSummary {
+		l.endOfDay()
+	}
+}
+
+// endOfDay reviews the day: the tasks completed today, and the open tasks due tomorrow.
+func (l *TaskList) endOfDay() {
+	today := startOfDay(l.now())
+	tomorrow := today.AddDate(0, 0, 1)
+
+	var completed, dueTomorrow []taskRef
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			ref := taskRef{project: project, task: task}
+			if task.IsDone() && !task.GetCompleted().Before(today) {
+				completed = append(completed, ref)
+			} else if !task.IsDone() && task.IsDueOn(tomorrow) {
+				dueTomorrow = append(dueTomorrow, ref)
+			}
+		}
+	}
+
+	if len(completed)+len(dueTomorrow) == 0 {
+		fmt.Fprintln(l.out, "Nothing completed today, nothing due tomorrow.")
+		return
+	}
+	l.printAgendaSection("Completed today", completed)
+	l.printAgendaSection("Due tomorrow", dueTomorrow)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Codes identifying the kind of a command failure in JSON error output.
+const (
+	codeUsage           = "usage"
+	codeUnknownCommand  = "unknown_command"
+	codeInvalidID       = "invalid_id"
+	codeTaskNotFound    = "task_not_found"
+	codeProjectNotFound = "project_not_found"
+	codeInvalidSetting  = "invalid_setting"
+	codeInvalidColumn   = "invalid_column"
+	codeImportFailed    = "import_failed"
+	codeExportFailed    = "export_failed"
+	codeInvalidDate     = "invalid_date"
+	codeGoalNotFound    = "goal_not_found"
+	codeHabitNotFound   = "habit_not_found"
+	codeInvalidQuery    = "invalid_query"
+	codeProjectNotEmpty = "project_not_empty"
+	codeDuplicateID     = "duplicate_id"
+	codeDuplicateTask   = "duplicate_task"
+	codeInternal        = "internal_error"
+	codeProjectExists   = "project_exists"
+	codeOpenSubtasks    = "open_subtasks"
+	codeBlocked         = "blocked"
+	codeDependencyCycle = "dependency_cycle"
+	codeNothingToUndo   = "nothing_to_undo"
+	codeNothingToRedo   = "nothing_to_redo"
+	codeTaskNotDone     = "task_not_done"
+	codeNotifyFailed    = "notify_failed"
+	codeNotTracking     = "not_tracking"
+)
+
+// commandError describes a command failure, as reported in JSON error output.
+type commandError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Command string `json:"command"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// fail reports a command failure to the user. By default, the message is written
+// on the output, between the results of the commands; in batch mode, it is written
+// on the error output with its line number; in JSON mode, a structured error naming
+// the offending command is written on the error output instead.
+func (l *TaskList) fail(code, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	l.usage.failedCode = code
+	if l.jsonErrors {
+		l.writeJSONError(commandError{Code: code, Message: message, Command: l.command, Line: l.line})
+		return
+	}
+	if l.line > 0 {
+		fmt.Fprintf(l.errOut, "line %d: %s\n", l.line, message)
+		return
+	}
+	fmt.Fprintln(l.out, message)
+}
+
+// errorCode returns the code of an error returned by a command: most are usage errors.
+func errorCode(err error) string {
+	var crash crashError
+	if errors.As(err, &crash) {
+		return codeInternal
+	}
+	return codeUsage
+}
+
+func (l *TaskList) writeJSONError(cmdErr commandError) {
+	encoder := json.NewEncoder(l.errOut)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(cmdErr); err != nil {
+		fmt.Fprintln(l.errOut, cmdErr.Message)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	estimateUsage = "could not execute estimate. Usage: estimate <task ID> <duration, e.g. 45m or 2h>|none"
+	reportUsage   = "could not execute report. Usage: report estimates"
+)
+
+// estimate sets how long a task is expected to take, or removes its estimate.
+func (l *TaskList) estimate(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf(estimateUsage)
+	}
+	estimate := time.Duration(0)
+	if args[1] != "none" {
+		var err error
+		if estimate, err = time.ParseDuration(args[1]); err != nil || estimate <= 0 {
+			return fmt.Errorf(estimateUsage)
+		}
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.estimate = estimate
+	return nil
+}
+
+func renderEstimate(task *Task, _ renderOptions) string {
+	if task.estimate == 0 {
+		return ""
+	}
+	return "~" + formatMinutes(task.estimate)
+}
+
+// estimateDetail describes the estimate of a task for the detailed display.
+func estimateDetail(task *Task) (string, bool) {
+	if task.estimate == 0 {
+		return "", false
+	}
+	return "estimate: " + formatMinutes(task.estimate), true
+}
+
+func (l *TaskList) report(args []string) error {
+	if len(args) != 1 || args[0] != "estimates" {
+		return fmt.Errorf(reportUsage)
+	}
+	l.reportEstimates()
+	return nil
+}
+
+// reportEstimates compares, per project, the time estimated for tasks with the time
+// actually spent on them.
+func (l *TaskList) reportEstimates() {
+	found := false
+	for _, project := range l.sortedProjects() {
+		var estimated, spent time.Duration
+		count := 0
+		for _, task := range l.projectTasks[project] {
+			if task.estimate > 0 {
+				estimated += task.estimate
+				spent += task.spent
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		if !found {
+			fmt.Fprintln(l.out, "Estimated vs spent time")
+			found = true
+		}
+		deviation := 100 * (spent - estimated) / estimated
+		fmt.Fprintf(l.out, "    %s: estimated %s, spent %s (%+d%%) over %s\n",
+			project, formatMinutes(estimated), formatMinutes(spent), deviation, countOf(count, "task"))
+	}
+	if !found {
+		fmt.Fprintln(l.out, "No task has an estimate.")
+	}
+}
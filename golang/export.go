@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+)
+
+const exportUsage = "could not execute export. Usage: export feed <project name> <file|-> [--where <query>] | export site <directory> [--where <query>]"
+
+func (l *TaskList) export(args []string) error {
+	args, where, err := exportFilter(args)
+	if err != nil {
+		return err
+	}
+	q := query{}
+	if where != "" {
+		if q, err = parseQuery(where, startOfDay(l.now())); err != nil {
+			l.fail(codeInvalidQuery, "Invalid query \"%s\": %v.", where, err)
+			return nil
+		}
+	}
+	if len(args) < 1 {
+		return fmt.Errorf(exportUsage)
+	}
+	switch args[0] {
+	case "feed":
+		if len(args) < 3 {
+			return fmt.Errorf(exportUsage)
+		}
+		l.exportFeed(args[1], args[2], q)
+	case "site":
+		if len(args) < 2 {
+			return fmt.Errorf(exportUsage)
+		}
+		l.exportSite(args[1], q)
+	default:
+		return fmt.Errorf(exportUsage)
+	}
+	return nil
+}
+
+// exportFilter takes the --where <query> option out of the arguments of export.
+// Every exporter only writes the tasks matching the query, or all of them without one.
+func exportFilter(args []string) ([]string, string, error) {
+	var rest []string
+	where := ""
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--where" {
+			rest = append(rest, args[i])
+			continue
+		}
+		if i+1 == len(args) {
+			return nil, "", fmt.Errorf(exportUsage)
+		}
+		i++
+		where = args[i]
+	}
+	return rest, where, nil
+}
+
+// scopedTasks returns the tasks of a project matching the filter of an export.
+func (l *TaskList) scopedTasks(project string, where query) []*Task {
+	var tasks []*Task
+	for _, task := range l.projectTasks[project] {
+		if where.Matches(taskRef{project: project, task: task}) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
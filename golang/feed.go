@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	atomNamespace = "http://www.w3.org/2005/Atom"
+	// feedRecentDays is how long completed tasks stay listed in a project feed.
+	feedRecentDays = 7
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID      string `xml:"id"`
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Content string `xml:"content"`
+}
+
+// exportFeed writes an Atom feed of the open and recently completed tasks of a project,
+// to a file or to the output when the path is "-". Only the tasks matching where are listed.
+func (l *TaskList) exportFeed(projectName, path string, where query) {
+	project, ok := l.findProject(projectName)
+	if !ok {
+		l.fail(codeProjectNotFound, "Could not find a project with the name \"%s\".", projectName)
+		return
+	}
+	projectName = project
+	tasks := l.scopedTasks(projectName, where)
+	file, err := l.createOutput(path)
+	if err != nil {
+		l.fail(codeExportFailed, "Could not export feed: %v.", err)
+		return
+	}
+	defer file.Close()
+
+	if err := writeFeed(file, projectName, tasks, l.now()); err != nil {
+		l.fail(codeExportFailed, "Could not export feed: %v.", err)
+		return
+	}
+	if path != streamPath {
+		fmt.Fprintf(l.out, "Exported feed of project \"%s\" to %s.\n", projectName, path)
+	}
+}
+
+func writeFeed(out io.Writer, projectName string, tasks []*Task, now time.Time) error {
+	feed := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      "urn:task-list:project:" + projectName,
+		Title:   projectName,
+		Updated: now.Format(time.RFC3339),
+		Author:  atomAuthor{Name: "task-list"},
+	}
+	recent := now.AddDate(0, 0, -feedRecentDays)
+	for _, task := range tasks {
+		if task.IsDone() && task.GetCompleted().Before(recent) {
+			continue
+		}
+		feed.Entries = append(feed.Entries, feedEntry(task))
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
+
+func feedEntry(task *Task) atomEntry {
+	updated, status := task.GetCreated(), "Open"
+	if task.IsDone() {
+		updated, status = task.GetCompleted(), "Done"
+	}
+	content := status
+	if due, ok := task.deadline.Date(); ok {
+		content += ", due " + due.Format("2006-01-02")
+	}
+	return atomEntry{
+		ID:      fmt.Sprintf("urn:task-list:task:%s", task.GetID()),
+		Title:   fmt.Sprintf("%s %s", renderStatus(task, renderOptions{}), task.GetDescription()),
+		Updated: updated.Format(time.RFC3339),
+		Content: content,
+	}
+}
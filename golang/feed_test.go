@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteFeed(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.UTC)
+	open := NewTask("1", "Eat more donuts.", false, now.AddDate(0, 0, -1))
+	open.SetDeadline(deadline{value: 20240612, date: "20240612"})
+	recent := NewTask("2", "Destroy all humans.", false, now.AddDate(0, 0, -20))
+	recent.SetDone(true, now.AddDate(0, 0, -2))
+	old := NewTask("3", "Buy a cat.", false, now.AddDate(0, 0, -20))
+	old.SetDone(true, now.AddDate(0, 0, -10))
+
+	var out bytes.Buffer
+	if err := writeFeed(&out, "secrets", []*Task{open, recent, old}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>urn:task-list:project:secrets</id>
+  <title>secrets</title>
+  <updated>2024-06-10T09:00:00Z</updated>
+  <author>
+    <name>task-list</name>
+  </author>
+  <entry>
+    <id>urn:task-list:task:1</id>
+    <title>[ ] Eat more donuts.</title>
+    <updated>2024-06-09T09:00:00Z</updated>
+    <content>Open, due 2024-06-12</content>
+  </entry>
+  <entry>
+    <id>urn:task-list:task:2</id>
+    <title>[X] Destroy all humans.</title>
+    <updated>2024-06-08T09:00:00Z</updated>
+    <content>Done</content>
+  </entry>
+</feed>
+`
+	if out.String() != expected {
+		t.Errorf("unexpected feed\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+)
+
+// showMatching prints, grouped by project, the tasks accepted by keep.
+// Projects without any accepted task are left out.
+func (l *TaskList) showMatching(keep func(task *Task) bool) {
+	table := l.newTaskTable()
+	found := false
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			if keep(task) {
+				table.Add(task)
+			}
+		}
+		if len(table.rows) == 0 {
+			continue
+		}
+		found = true
+		fmt.Fprintln(l.out, project)
+		table.Flush(l.out)
+		fmt.Fprintln(l.out)
+	}
+	if !found {
+		fmt.Fprintln(l.out, "No matching tasks.")
+	}
+}
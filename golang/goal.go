@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	goalUsage      = "could not execute goal. Usage: goal add <name> [--by <YYYY-MM-DD>] | goal link <task ID> <goal ID>"
+	goalDateLayout = "2006-01-02"
+)
+
+// Goal is an outcome that tasks contribute to, with an optional target date.
+type Goal struct {
+	id    int
+	name  string
+	by    time.Time
+	hasBy bool
+}
+
+func (l *TaskList) goal(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf(goalUsage)
+	}
+	switch args[0] {
+	case "add":
+		return l.addGoal(args[1:])
+	case "link":
+		if len(args) < 3 {
+			return fmt.Errorf(goalUsage)
+		}
+		l.linkGoal(args[1], args[2])
+	default:
+		return fmt.Errorf(goalUsage)
+	}
+	return nil
+}
+
+func (l *TaskList) addGoal(args []string) error {
+	var nameWords []string
+	goal := &Goal{id: len(l.goals) + 1}
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--by" {
+			nameWords = append(nameWords, args[i])
+			continue
+		}
+		if i+1 == len(args) {
+			return fmt.Errorf(goalUsage)
+		}
+		by, err := parseGoalDate(args[i+1])
+		if err != nil {
+			l.fail(codeInvalidDate, "Invalid date \"%s\".", args[i+1])
+			return nil
+		}
+		goal.by, goal.hasBy = by, true
+		i++
+	}
+	if len(nameWords) == 0 {
+		return fmt.Errorf(goalUsage)
+	}
+	goal.name = strings.Join(nameWords, " ")
+	l.goals = append(l.goals, goal)
+	fmt.Fprintf(l.out, "Added goal %d: %s\n", goal.id, goal.name)
+	return nil
+}
+
+func parseGoalDate(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation(goalDateLayout, value, time.Local); err == nil {
+		return date, nil
+	}
+	return time.ParseInLocation(deadlineLayout, value, time.Local)
+}
+
+func (l *TaskList) linkGoal(taskID, goalID string) {
+	goal, ok := l.goalBy(goalID)
+	if !ok {
+		l.fail(codeGoalNotFound, "Goal with ID \"%s\" not found.", goalID)
+		return
+	}
+	task, err := l.getTaskBy(taskID)
+	if err != nil {
+		return
+	}
+	task.goal = goal.id
+	fmt.Fprintf(l.out, "Linked task %s to goal \"%s\".\n", task.GetID(), goal.name)
+}
+
+func (l *TaskList) goalBy(idString string) (*Goal, bool) {
+	id, err := strconv.Atoi(idString)
+	if err != nil || id < 1 || id > len(l.goals) {
+		return nil, false
+	}
+	return l.goals[id-1], true
+}
+
+// showGoals lists the goals with the completion of their linked tasks and the days left.
+func (l *TaskList) showGoals() {
+	if len(l.goals) == 0 {
+		fmt.Fprintln(l.out, "No goals yet.")
+		return
+	}
+	today := startOfDay(l.now())
+	for _, goal := range l.goals {
+		var linked []*Task
+		for _, tasks := range l.projectTasks {
+			for _, task := range tasks {
+				if task.goal == goal.id {
+					linked = append(linked, task)
+				}
+			}
+		}
+		line := fmt.Sprintf("%d: %s (%s)", goal.id, goal.name, progressOf(linked))
+		if goal.hasBy {
+			line += ", " + daysRemaining(today, goal.by)
+		}
+		fmt.Fprintln(l.out, line)
+	}
+}
+
+// daysRemaining describes how far the date is from today, in calendar days. They are
+// counted between the starts of both days taken in UTC, so that neither a time of day
+// nor a change of daylight saving time shifts the count.
+func daysRemaining(today, date time.Time) string {
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(to.Sub(from).Hours() / 24)
+	switch {
+	case days == 0:
+		return "due today"
+	case days < 0:
+		return countOf(-days, "day") + " overdue"
+	}
+	return countOf(days, "day") + " left"
+}
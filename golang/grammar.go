@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	taskUsage    = "could not execute task. Usage: task add|check|uncheck|edit|move|delete|deadline|context|size|priority|tag|untag|snooze|repeat|depends|note|remind|start|estimate|progress|assign ..."
+	projectUsage = "could not execute project. Usage: project add|rename|delete <project name> ..."
+)
+
+// taskVerbs are the commands on a single task, grouped under "task".
+var taskVerbs = map[string]bool{
+	"check": true, "uncheck": true, "edit": true, "move": true, "delete": true,
+	"deadline": true, "context": true, "size": true, "priority": true,
+	"tag": true, "untag": true, "snooze": true, "repeat": true, "depends": true, "note": true,
+	"remind": true, "start": true, "estimate": true, "progress": true, "assign": true,
+}
+
+// ungroup translates the grouped grammar, e.g. "task check 1" or "project rename a b",
+// into the flat commands it stands for, e.g. "check 1" or "rename project a b".
+// Flat commands are returned unchanged.
+func ungroup(args []string) ([]string, error) {
+	switch args[0] {
+	case "task":
+		if len(args) < 2 {
+			return nil, fmt.Errorf(taskUsage)
+		}
+		if args[1] == "add" {
+			return append([]string{"add", "task"}, args[2:]...), nil
+		}
+		if !taskVerbs[args[1]] {
+			return nil, fmt.Errorf(taskUsage)
+		}
+		return args[1:], nil
+	case "project":
+		if len(args) < 2 {
+			return nil, fmt.Errorf(projectUsage)
+		}
+		switch args[1] {
+		case "add", "rename", "delete":
+			return append([]string{args[1], "project"}, args[2:]...), nil
+		}
+		return nil, fmt.Errorf(projectUsage)
+	}
+	return args, nil
+}
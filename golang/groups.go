@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// viewByDate prints every task grouped by the day it was created, oldest day first.
+func (l *TaskList) viewByDate() {
+	l.printByDay(func(task *Task) (time.Time, bool) { return task.GetCreated(), true }, "")
+}
+
+// viewByDeadline prints every task grouped by its deadline, earliest first, followed by
+// the tasks without a deadline date.
+func (l *TaskList) viewByDeadline() {
+	l.printByDay(func(task *Task) (time.Time, bool) { return task.deadline.Date() }, "No deadline")
+}
+
+// printByDay prints the tasks grouped by the day dayOf gives them, in chronological order,
+// then the tasks without a day under the none title.
+func (l *TaskList) printByDay(dayOf func(task *Task) (time.Time, bool), none string) {
+	var dated, undated []taskRef
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			if _, ok := dayOf(task); ok {
+				dated = append(dated, taskRef{project: project, task: task})
+			} else {
+				undated = append(undated, taskRef{project: project, task: task})
+			}
+		}
+	}
+	if len(dated)+len(undated) == 0 {
+		fmt.Fprintln(l.out, "No tasks.")
+		return
+	}
+	dayOfRef := func(ref taskRef) time.Time {
+		day, _ := dayOf(ref.task)
+		return startOfDay(day)
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		a, _ := dayOf(dated[i].task)
+		b, _ := dayOf(dated[j].task)
+		return a.Before(b)
+	})
+
+	start := 0
+	for i := 1; i <= len(dated); i++ {
+		if i < len(dated) && dayOfRef(dated[i]).Equal(dayOfRef(dated[start])) {
+			continue
+		}
+		l.printAgendaSection(dayOfRef(dated[start]).Format("2006-01-02"), dated[start:i])
+		start = i
+	}
+	l.printAgendaSection(none, undated)
+}
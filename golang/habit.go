@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const habitUsage = "could not execute habit. Usage: habit add <description> daily|weekly | habit done <habit ID>"
+
+// frequency is how often a habit is expected to be done.
+type frequency string
+
+const (
+	daily  frequency = "daily"
+	weekly frequency = "weekly"
+)
+
+// Habit is a recurring checklist item, done once per period and tracked by streaks,
+// independently from the tasks of the projects.
+type Habit struct {
+	id          int
+	description string
+	frequency   frequency
+	donePeriods map[string]bool
+}
+
+// period returns the key of the day or week containing t, depending on the frequency.
+func (h *Habit) period(t time.Time) string {
+	if h.frequency == weekly {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	}
+	return t.Format(deadlineLayout)
+}
+
+func (h *Habit) previousPeriod(t time.Time) time.Time {
+	if h.frequency == weekly {
+		return t.AddDate(0, 0, -7)
+	}
+	return t.AddDate(0, 0, -1)
+}
+
+// IsDone returns whether the habit was done in the period containing now.
+func (h *Habit) IsDone(now time.Time) bool {
+	return h.donePeriods[h.period(now)]
+}
+
+// Streak counts the consecutive periods the habit was done, up to now. The current
+// period not being done yet does not break the streak.
+func (h *Habit) Streak(now time.Time) int {
+	streak := 0
+	t := now
+	if !h.IsDone(now) {
+		t = h.previousPeriod(now)
+	}
+	for h.donePeriods[h.period(t)] {
+		streak++
+		t = h.previousPeriod(t)
+	}
+	return streak
+}
+
+func (l *TaskList) habit(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf(habitUsage)
+	}
+	switch args[0] {
+	case "add":
+		last := len(args) - 1
+		f := frequency(args[last])
+		if last < 2 || (f != daily && f != weekly) {
+			return fmt.Errorf(habitUsage)
+		}
+		habit := &Habit{
+			id:          len(l.habits) + 1,
+			description: strings.Join(args[1:last], " "),
+			frequency:   f,
+			donePeriods: map[string]bool{},
+		}
+		l.habits = append(l.habits, habit)
+		fmt.Fprintf(l.out, "Added %s habit %d: %s\n", habit.frequency, habit.id, habit.description)
+	case "done":
+		id, err := strconv.Atoi(args[1])
+		if err != nil || id < 1 || id > len(l.habits) {
+			l.fail(codeHabitNotFound, "Habit with ID \"%s\" not found.", args[1])
+			return nil
+		}
+		habit := l.habits[id-1]
+		habit.donePeriods[habit.period(l.now())] = true
+		fmt.Fprintf(l.out, "Habit %d done, streak %d.\n", habit.id, habit.Streak(l.now()))
+	default:
+		return fmt.Errorf(habitUsage)
+	}
+	return nil
+}
+
+// showHabits lists the habits with their status in the current period and their streak.
+func (l *TaskList) showHabits() {
+	if len(l.habits) == 0 {
+		fmt.Fprintln(l.out, "No habits yet.")
+		return
+	}
+	now := l.now()
+	for _, habit := range l.habits {
+		done := ' '
+		if habit.IsDone(now) {
+			done = 'X'
+		}
+		fmt.Fprintf(l.out, "    [%c] %d: %s (%s, streak %d)\n", done, habit.id, habit.description, habit.frequency, habit.Streak(now))
+	}
+}
@@ -5,8 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 var (
@@ -41,16 +47,59 @@ const (
 	// Quit is the text command used to quit the task manager.
 	TaskNotFoundErr        = Error("Task not found")
 	Quit            string = "quit"
-	prompt          string = "> "
+	// prompt is the default template of the prompt shown before each command.
+	prompt string = "> "
 )
 
 // TaskList is a set of tasks, grouped by project.
 type TaskList struct {
-	in  io.Reader
-	out io.Writer
+	in      io.Reader
+	out     io.Writer
+	errOut  io.Writer
+	scanner *lineReader
+	// batch is set when commands are not typed by a user, who could answer questions.
+	batch bool
+	// line is the number of the input line being run in batch mode, 0 otherwise.
+	line int
 
 	projectTasks map[string][]*Task
+	goals        []*Goal
+	habits       []*Habit
 	lastID       int64
+	// chosenIDs holds the IDs given by the user, which the numbering skips.
+	chosenIDs map[identifier]bool
+	now       func() time.Time
+	settings  settings
+	// archived holds the tasks moved out of the projects by archive, per project.
+	archived map[string][]*Task
+	// currentProject is the project most recently added to.
+	currentProject string
+	lastRollover   time.Time
+	// command is the command line being executed.
+	command    string
+	jsonErrors bool
+	// usage counts the commands run, when telemetry is on.
+	usage usageReport
+	// timings receives the duration of each command when set.
+	timings io.Writer
+	// crashLog is the file where the stack traces of failing commands are appended.
+	crashLog string
+	// undoStack holds the operations of the commands undo can revert, redoStack those
+	// of the commands undone, and pendingUndo those of the command being run.
+	undoStack   []*undoStep
+	redoStack   []*undoStep
+	pendingUndo *undoStep
+	// recording receives the commands typed since record, in the file at recordPath.
+	recording  io.WriteCloser
+	recordPath string
+	replaying  bool
+	// tracked is the task started for time tracking, since trackedSince.
+	tracked      *Task
+	trackedSince time.Time
+	// focus is the pomodoro running in the background, if any.
+	focus *focusTimer
+	// mu keeps the reminders given in the background from interleaving with commands.
+	mu sync.Mutex
 }
 
 // NewTaskList initializes a TaskList on the given I/O descriptors.
@@ -58,191 +107,786 @@ func NewTaskList(in io.Reader, out io.Writer) *TaskList {
 	return &TaskList{
 		in:           in,
 		out:          out,
+		errOut:       out,
+		crashLog:     filepath.Join(os.TempDir(), "task-list-crash.log"),
 		projectTasks: make(map[string][]*Task),
 		lastID:       0,
+		now:          time.Now,
+		settings:     defaultSettings(),
 	}
 }
 
 // Run runs the command loop of the task manager.
-// Sequentially executes any given command, until the user types the Quit message.
+// Sequentially executes any given command, until the user types the Quit message
+// or the input ends. A failing command is sent on errorsChan, then reported with its
+// usage, and the session goes on; so is a failure to read the input, ending it.
 func (l *TaskList) Run(errorsChan chan<- error, shutdownChan chan bool) {
-	scanner := bufio.NewScanner(l.in)
+	scanner := l.input()
 
-	fmt.Fprint(l.out, prompt)
+	l.printPrompt()
 	for scanner.Scan() {
 		cmdLine := scanner.Text()
 		if cmdLine == Quit {
-			shutdownChan <- true
-			return
+			break
 		}
 
+		l.mu.Lock()
 		err := l.execute(cmdLine)
+		l.mu.Unlock()
 		if err != nil {
 			errorsChan <- err
-			fmt.Printf("program exited, %v", err)
+			l.mu.Lock()
+			l.fail(errorCode(err), "%v", err)
+			l.mu.Unlock()
 		}
-		fmt.Fprint(l.out, prompt)
+		l.printPrompt()
 	}
+	if err := scanner.Err(); err != nil {
+		errorsChan <- err
+		l.mu.Lock()
+		l.fail(codeInternal, "Could not read the input: %v", err)
+		l.mu.Unlock()
+	}
+	l.mu.Lock()
+	l.EndSession()
+	l.mu.Unlock()
+	shutdownChan <- true
 }
 
-func (l *TaskList) execute(cmdLine string) error {
-	args := strings.Split(cmdLine, " ")
-	command := args[0]
-	switch command {
-	case "show":
-		l.show()
-	case "add":
-		if len(args) < 2 {
-			return fmt.Errorf("could not execute add, it requires at least 2 parameters")
-		}
-		l.add(args[1:])
-	case "check":
-		l.check(args[1])
-	case "uncheck":
-		l.uncheck(args[1])
-	case "help":
-		l.help()
-	case "deadline":
-		if len(args) < 2 {
-			return fmt.Errorf("could not execute deadline. Usage: deadline <taskId> <dateAsString>")
-		}
-		l.deadline(args[1], args[2])
-	case "today":
-		l.today()
-	default:
-		l.error(command)
+// EnableJSONErrors reports command failures as JSON objects, one per line, on errOut.
+func (l *TaskList) EnableJSONErrors(errOut io.Writer) {
+	l.errOut = errOut
+	l.jsonErrors = true
+}
+
+// RunBatch executes every command read from the input, without prompting.
+// A failing command, whether it returned an error or reported a failure, is reported
+// on errOut with its line number, and execution goes on with the next line. It stops
+// at the end of the input or at the Quit message, and returns an error when at least
+// one command failed.
+func (l *TaskList) RunBatch(errOut io.Writer) error {
+	l.batch = true
+	l.errOut = errOut
+	defer func() { l.line = 0 }()
+	scanner := l.input()
+
+	executed, failures := 0, 0
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		cmdLine := scanner.Text()
+		if cmdLine == Quit {
+			break
+		}
+
+		executed++
+		l.line = lineNumber
+		err := l.execute(cmdLine)
+		if err == nil && l.usage.failedCode != "" {
+			failures++
+		}
+		if err != nil {
+			failures++
+			if l.jsonErrors {
+				l.writeJSONError(commandError{Code: errorCode(err), Message: err.Error(), Command: cmdLine, Line: lineNumber})
+				continue
+			}
+			fmt.Fprintf(errOut, "line %d: %v\n", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d commands failed", failures, executed)
 	}
 	return nil
 }
 
+// input returns the scanner reading lines from the input, shared by the command loop
+// and the commands asking questions.
+func (l *TaskList) input() *lineReader {
+	if l.scanner == nil {
+		l.scanner = &lineReader{reader: bufio.NewReader(l.in)}
+	}
+	return l.scanner
+}
+
+// RunArgs executes a single command given as separate words, e.g. the arguments of the
+// program. The input is then left to importers reading "-", so no question can be asked.
+func (l *TaskList) RunArgs(args []string) error {
+	l.batch = true
+	l.command = strings.Join(args, " ")
+	return l.dispatchTimed(args, 0)
+}
+
+// EnableTimings reports on w how long parsing and running each command took.
+func (l *TaskList) EnableTimings(w io.Writer) {
+	l.timings = w
+}
+
+func (l *TaskList) execute(cmdLine string) error {
+	l.command = cmdLine
+	l.recordCommand(cmdLine)
+	start := time.Now()
+	args, err := tokenize(cmdLine)
+	if err != nil {
+		return err
+	}
+	return l.dispatchTimed(args, time.Since(start))
+}
+
+// dispatchTimed runs a command, reporting how long it took when timings are enabled.
+func (l *TaskList) dispatchTimed(args []string, parse time.Duration) error {
+	if l.timings == nil {
+		return l.dispatch(args)
+	}
+	start := time.Now()
+	err := l.dispatch(args)
+	fmt.Fprintf(l.timings, "timings: %q parse=%v run=%v\n", l.command, parse, time.Since(start))
+	return err
+}
+
+func (l *TaskList) dispatch(args []string) (err error) {
+	if len(args) == 0 {
+		return nil
+	}
+	l.usage.failedCode = ""
+	defer func() {
+		if l.settings.telemetry {
+			l.usage.record(args[0], err != nil)
+		}
+	}()
+	defer l.recoverCrash(&err)
+	return l.runCommand(args)
+}
+
+func (l *TaskList) runCommand(args []string) error {
+	args, err := ungroup(args)
+	if err != nil {
+		return err
+	}
+	l.autoRollover()
+	l.beginUndo()
+	defer l.endUndo(args)
+	handler, ok := commands[args[0]]
+	if !ok {
+		l.error(args[0])
+		return nil
+	}
+	return handler(l, args[1:])
+}
+
 func (l *TaskList) help() {
 	fmt.Fprintln(l.out, `Commands:
-  show
-  add project <project name>
-  add task <project name> <task description>
-  check <task ID>
-  uncheck <task ID>
+  tutorial
+  show [--columns <column>,...]
+  show pending|done|archived
+  show tag:<label>
+  show assignee:<person>
+  show <task ID>
+  project add <project name>
+  project rename <old name> <new name>
+  project delete <project name> [--force]
+  task add <project name> [--id <ID>] <task description>
+  add subtask <parent task ID> <task description>
+  task check|uncheck <task ID> [<task ID>...]
+  task edit <task ID> <new description>
+  task note <task ID> <text>
+  task move <task ID> <project name>
+  task delete <task ID> [<task ID>...]
+  task deadline <task ID> <YYYYMMDD|YYYY-MM-DDTHH:MM|+<N>d|+<N>w|+<N>bd|tomorrow|friday|"next week">
+  task snooze <task ID> <N>d|<N>w|<N>bd
+  task repeat <task ID> daily|weekly|monthly|none
+  task start <task ID>
+  stop
+  task progress <task ID> <0-100>
+  task assign <task ID> <person>|none
+  task estimate <task ID> <duration, e.g. 45m or 2h>|none
+  report estimates
+  pomodoro <task ID>|stop
+  task remind <task ID> <lead time, e.g. 30m, 2h or 1d>|none
+  task depends <task ID> on <task ID>|none
+  task context <task ID> <@context>...|none
+  task size <task ID> S|M|L|none
+  task priority <task ID> high|medium|low|none
+  task tag|untag <task ID> <label>...
+  archive <task ID>|done
+  clear completed [<project name>] [--archive]
+  undo
+  redo
+  today
+  agenda
+  due <number of days>
+  week
+  calendar [<YYYYMM>]
+  brief
+  rebalance
+  rollover
+  yesterday
+  search <text>
+  apply [--dry-run] <query> <action>
+  replace [--yes] <query> "<old>" "<new>"
+  goal add <name> [--by <YYYY-MM-DD>]
+  goal link <task ID> <goal ID>
+  goals
+  habit add <description> daily|weekly
+  habit done <habit ID>
+  habits
+  view by project [--columns <column>,...]
+  view by date|deadline|priority
+  view calendar [<YYYYMM>]
+  view quick
+  view <@context>
+  set [<setting> <value>]
+  telemetry show
+  notify test [<channel>]
+  record <file>|stop
+  replay <file|->
+  bench [--tasks <count>] [--ops <count>]
+  doctor
+  import email <project name> <file|->
+  import csv <mapping file> <file|->
+  export feed <project name> <file|-> [--where <query>]
+  export site <directory> [--where <query>]
+The task and project commands are also accepted without their group,
+e.g. "check <task ID>" or "add project <project name>".
   `)
 }
 
-func (l *TaskList) error(command string) {
-	fmt.Fprintf(l.out, "Unknown command \"%s\".\n", command)
+const viewUsage = "could not execute view. Usage: view by project|date|deadline|priority | view calendar [<YYYYMM>] | view quick | view <@context>"
+
+func (l *TaskList) view(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf(viewUsage)
+	}
+	switch {
+	case args[0] == "by":
+		return l.viewBy(args[1:])
+	case args[0] == "calendar":
+		return l.calendar(args[1:])
+	case args[0] == "quick":
+		l.showMatching(isQuickWin)
+	case isContext(args[0]):
+		l.showMatching(func(task *Task) bool { return task.HasContext(args[0]) })
+	default:
+		l.error("view " + args[0])
+	}
+	return nil
 }
 
-func (l *TaskList) today() {
-	// sort projects (to make output deterministic)
-	sortedProjects := make([]string, 0, len(l.projectTasks))
-	for project := range l.projectTasks {
-		sortedProjects = append(sortedProjects, project)
+// viewBy groups the tasks by project, like show, by creation date or by deadline.
+func (l *TaskList) viewBy(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf(viewUsage)
 	}
-	sort.Sort(sort.StringSlice(sortedProjects))
+	switch {
+	case args[0] == "project":
+		return l.show(args[1:])
+	case args[0] == "date" && len(args) == 1:
+		l.viewByDate()
+	case args[0] == "deadline" && len(args) == 1:
+		l.viewByDeadline()
+	case args[0] == "priority" && len(args) == 1:
+		l.viewByPriority()
+	default:
+		return fmt.Errorf(viewUsage)
+	}
+	return nil
+}
 
+func (l *TaskList) set(args []string) {
+	if len(args) < 2 {
+		for _, key := range l.settings.Keys() {
+			fmt.Fprintf(l.out, "%s = %s\n", key, l.settings.Get(key))
+		}
+		return
+	}
+	if err := l.settings.Set(args[0], strings.Join(args[1:], " ")); err != nil {
+		l.fail(codeInvalidSetting, "Could not change setting: %v.", err)
+	}
+}
+
+func (l *TaskList) error(command string) {
+	l.fail(codeUnknownCommand, "Unknown command \"%s\".%s", command, suggestCommand(command))
+}
+
+func (l *TaskList) today() {
+	now := l.now()
 	// show projects sequentially
-	for _, project := range sortedProjects {
-		tasks := l.projectTasks[project]
+	table := l.newTaskTable()
+	for _, project := range l.sortedProjects() {
+		tasks := l.byPriority(l.projectTasks[project])
 		fmt.Fprintf(l.out, "%s\n", project)
 		for _, task := range tasks {
-			if task.IsPreviousToCurrentDate() {
-				done := ' '
-				if task.IsDone() {
-					done = 'X'
-				}
-				fmt.Fprintf(l.out, "    [%c] %d:%s %s\n", done, task.GetID(), task.GetDeadline(), task.GetDescription())
+			if task.IsPreviousToDate(now) {
+				table.Add(task, dueMarker(task, now)...)
 			}
 		}
+		table.Flush(l.out)
 		fmt.Fprintln(l.out)
 	}
 }
 
-func (l *TaskList) show() {
-	// sort projects (to make output deterministic)
+// dueMarker tells whether an open task due at a time of day today is due later today,
+// or already past, at the time the tasks of today were selected.
+func dueMarker(task *Task, now time.Time) []string {
+	if task.IsDone() || task.deadline.clock == "" || !task.IsDueOn(now) {
+		return nil
+	}
+	if due, _ := task.deadline.Due(); now.Before(due) {
+		return []string{"due later today"}
+	}
+	return []string{"already past"}
+}
+
+func (l *TaskList) show(args []string) error {
+	if len(args) == 1 {
+		switch {
+		case args[0] == "pending":
+			l.showMatching(func(task *Task) bool { return !task.IsDone() })
+			return nil
+		case args[0] == "done":
+			l.showMatching((*Task).IsDone)
+			return nil
+		case args[0] == "archived":
+			l.showArchived()
+			return nil
+		case strings.HasPrefix(args[0], tagPrefix):
+			tag := strings.TrimPrefix(args[0], tagPrefix)
+			l.showMatching(func(task *Task) bool { return task.HasTag(tag) })
+			return nil
+		case strings.HasPrefix(args[0], assigneePrefix):
+			person := strings.TrimPrefix(args[0], assigneePrefix)
+			l.showMatching(func(task *Task) bool { return task.IsAssignedTo(person) })
+			return nil
+		case args[0] != "--columns":
+			if task, err := l.getTaskBy(args[0]); err == nil {
+				l.showTask(task)
+			}
+			return nil
+		}
+	}
+	table := l.newTaskTable()
+	if len(args) > 0 {
+		if args[0] != "--columns" || len(args) < 2 {
+			return fmt.Errorf("could not execute show. Usage: show [--columns <column>,...] | show pending|done|archived | show tag:<label> | show assignee:<person> | show <task ID>")
+		}
+		var err error
+		if table, err = newTaskTable(strings.Split(args[1], ","), l.renderOptions()); err != nil {
+			l.fail(codeInvalidColumn, "Could not show tasks: %v.", err)
+			return nil
+		}
+	}
+
+	// show projects sequentially
+	for _, project := range l.sortedProjects() {
+		tasks := l.byPriority(l.projectTasks[project])
+		fmt.Fprintln(l.out, l.projectHeader(project))
+		addTree(table, tasks)
+		table.Flush(l.out)
+		fmt.Fprintln(l.out)
+	}
+	if l.settings.footer {
+		fmt.Fprintln(l.out, l.summary())
+	}
+	return nil
+}
+
+// sortedProjects returns the project names in alphabetical order (to make output deterministic).
+func (l *TaskList) sortedProjects() []string {
 	sortedProjects := make([]string, 0, len(l.projectTasks))
 	for project := range l.projectTasks {
 		sortedProjects = append(sortedProjects, project)
 	}
 	sort.Sort(sort.StringSlice(sortedProjects))
+	return sortedProjects
+}
 
-	// show projects sequentially
-	for _, project := range sortedProjects {
-		tasks := l.projectTasks[project]
-		fmt.Fprintf(l.out, "%s\n", project)
-		for _, task := range tasks {
-			done := ' '
-			if task.IsDone() {
-				done = 'X'
-			}
-			fmt.Fprintf(l.out, "    [%c] %d:%s %s\n", done, task.GetID(), task.GetDeadline(), task.GetDescription())
-		}
-		fmt.Fprintln(l.out)
+// newTaskTable creates a table rendering tasks with the current display setting.
+func (l *TaskList) newTaskTable() *taskTable {
+	return newDisplayTaskTable(l.settings.display, l.renderOptions())
+}
+
+func (l *TaskList) renderOptions() renderOptions {
+	return renderOptions{
+		icons:           l.settings.icons.Enabled(l.out),
+		today:           startOfDay(l.now()),
+		agingThresholds: l.settings.agingThresholds,
+		workdays:        l.settings.workdays,
 	}
 }
 
-func (l *TaskList) add(args []string) {
+func (l *TaskList) add(args []string) error {
 	projectName := args[1]
 	if args[0] == "project" {
 		l.addProject(projectName)
+	} else if args[0] == "subtask" {
+		if len(args) < 3 {
+			return fmt.Errorf("could not execute add. Usage: add subtask <parent task ID> <task description>")
+		}
+		l.addSubtask(args[1], l.settings.descriptions.Apply(strings.Join(args[2:], " ")))
 	} else if args[0] == "task" {
-		description := strings.Join(args[2:], " ")
-		l.addTask(projectName, description)
+		var id identifier
+		words := args[2:]
+		if len(words) > 0 && words[0] == "--id" {
+			if len(words) < 2 {
+				return fmt.Errorf("could not execute add. Usage: add task <project name> [--id <ID>] <task description>")
+			}
+			custom, err := NewIdentifier(words[1])
+			if err != nil {
+				l.fail(codeInvalidID, "Invalid ID \"%s\", use only letters, digits, - and _.", words[1])
+				return nil
+			}
+			if l.findTask(custom) != nil || l.isArchived(custom) {
+				l.fail(codeDuplicateID, "ID \"%s\" is already used.", custom)
+				return nil
+			}
+			id, words = custom, words[2:]
+		}
+		description := l.settings.descriptions.Apply(strings.Join(words, " "))
+		if description == "" {
+			return fmt.Errorf("could not execute add. Usage: add task <project name> [--id <ID>] <task description>")
+		}
+		if duplicate, ok := l.findDuplicate(projectName, description); ok {
+			question := fmt.Sprintf("Task %s \"%s\" looks the same. Add anyway?", duplicate.GetID(), duplicate.GetDescription())
+			if !l.confirm(question) {
+				l.fail(codeDuplicateTask, "Task not added.")
+				return nil
+			}
+		}
+		l.addTaskWithID(projectName, id, description)
 	}
+	return nil
 }
 
 func (l *TaskList) addProject(name string) {
+	if existing, ok := l.findProject(name); ok {
+		l.fail(codeProjectExists, "A project named \"%s\" already exists.", existing)
+		return
+	}
 	l.projectTasks[name] = make([]*Task, 0)
+	l.currentProject = name
+	l.recordUndo(
+		func() { delete(l.projectTasks, name) },
+		func() { l.projectTasks[name] = make([]*Task, 0) },
+	)
 }
 
-func (l *TaskList) addTask(projectName, description string) {
-	tasks, ok := l.projectTasks[projectName]
+// findProject returns the name of the project a user refers to, ignoring case and
+// separators: "Shopping", "shopping" and "SHOPPING" all name the same project, as do
+// "Home Improvement" and "home-improvement", whose name stays as first entered.
+func (l *TaskList) findProject(name string) (string, bool) {
+	if _, ok := l.projectTasks[name]; ok {
+		return name, true
+	}
+	for project := range l.projectTasks {
+		if sameProject(project, name) {
+			return project, true
+		}
+	}
+	return "", false
+}
+
+// sameProject returns whether two project names have the same slug.
+func sameProject(a, b string) bool {
+	return projectSlug(a) == projectSlug(b)
+}
+
+// projectSlugSeparators are the characters which, like spaces, only separate the words
+// of a project name.
+const projectSlugSeparators = "-_./,:;"
+
+// projectSlug normalizes a project name for matching: letters are lowercased, and runs
+// of spaces and separators become a single dash, dropped at both ends. Other symbols are
+// kept, so "C" and "C++" remain two projects; a name of separators only is its own slug.
+func projectSlug(name string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsSpace(r) || strings.ContainsRune(projectSlugSeparators, r) {
+			dash = true
+			continue
+		}
+		if dash && slug.Len() > 0 {
+			slug.WriteRune('-')
+		}
+		slug.WriteRune(r)
+		dash = false
+	}
+	if slug.Len() == 0 {
+		return name
+	}
+	return slug.String()
+}
+
+// addTask creates a task in the named project, returning nil when the project does not exist.
+func (l *TaskList) addTask(projectName, description string) *Task {
+	return l.addTaskWithID(projectName, "", description)
+}
+
+// addTaskWithID creates a task with the given ID, or the next number when the ID is empty.
+func (l *TaskList) addTaskWithID(projectName string, id identifier, description string) *Task {
+	project, ok := l.findProject(projectName)
 	if !ok {
-		fmt.Fprintf(l.out, "Could not find a project with the name \"%s\".\n", projectName)
+		l.fail(codeProjectNotFound, "Could not find a project with the name \"%s\".", projectName)
+		return nil
+	}
+	projectName = project
+	tasks := l.projectTasks[projectName]
+	if id == "" {
+		id = l.nextID()
+	} else {
+		if l.chosenIDs == nil {
+			l.chosenIDs = map[identifier]bool{}
+		}
+		l.chosenIDs[id] = true
+	}
+	task := NewTask(id, description, false, l.now())
+	l.projectTasks[projectName] = append(tasks, task)
+	l.currentProject = projectName
+	l.recordUndo(
+		func() {
+			l.removeTask(task)
+			l.orphanSubtasks(task)
+			l.forgetBlocker(task)
+		},
+		func() { l.projectTasks[projectName] = append(l.projectTasks[projectName], task) },
+	)
+	return task
+}
+
+func (l *TaskList) setDone(idString string, done bool) {
+	task, err := l.getTaskBy(idString)
+	if err != nil {
 		return
 	}
-	l.projectTasks[projectName] = append(tasks, NewTask(l.nextID(), description, false))
+	l.complete(task, done)
 }
 
-func (l *TaskList) check(idString string) {
-	l.setDone(idString, true)
+// setDoneAll checks or unchecks several tasks at once. A single task is changed
+// silently as before; with several, the outcome for each ID is reported in turn.
+func (l *TaskList) setDoneAll(ids []string, done bool) {
+	if len(ids) == 1 {
+		l.setDone(ids[0], done)
+		return
+	}
+	verb := "Checked"
+	if !done {
+		verb = "Unchecked"
+	}
+	for _, idString := range ids {
+		task, err := l.getTaskBy(idString)
+		if err != nil {
+			continue
+		}
+		l.complete(task, done)
+		if task.IsDone() == done {
+			fmt.Fprintf(l.out, "%s task %s: %s\n", verb, task.GetID(), task.GetDescription())
+		}
+	}
 }
 
-func (l *TaskList) uncheck(idString string) {
-	l.setDone(idString, false)
+// edit replaces the description of a task, cleaned up like new descriptions are.
+func (l *TaskList) edit(idString, description string) {
+	task, err := l.getTaskBy(idString)
+	if err != nil {
+		return
+	}
+	previous := task.GetDescription()
+	task.description = l.settings.descriptions.Apply(description)
+	fmt.Fprintf(l.out, "Task %s: \"%s\" is now \"%s\".\n", task.GetID(), previous, task.GetDescription())
 }
 
-func (l *TaskList) setDone(idString string, done bool) {
+const deleteUsage = "could not execute delete. Usage: delete <task ID> [<task ID>...] | delete project <project name> [--force]"
+
+func (l *TaskList) delete(args []string) error {
+	if len(args) > 0 && args[0] != "project" {
+		for _, idString := range args {
+			l.deleteTask(idString)
+		}
+		return nil
+	}
+	if len(args) == 0 || len(args) > 3 || (len(args) == 3 && args[2] != "--force") {
+		return fmt.Errorf(deleteUsage)
+	}
+	l.deleteProject(args[1], len(args) == 3)
+	return nil
+}
+
+// renameProject moves the tasks of a project under a new name, keeping their IDs.
+func (l *TaskList) renameProject(oldName, newName string) {
+	project, ok := l.findProject(oldName)
+	if !ok {
+		l.fail(codeProjectNotFound, "Could not find a project with the name \"%s\".", oldName)
+		return
+	}
+	oldName = project
+	if existing, exists := l.findProject(newName); exists && existing != oldName {
+		l.fail(codeProjectExists, "A project named \"%s\" already exists.", existing)
+		return
+	}
+	tasks := l.projectTasks[oldName]
+	archived, archivedUnderNew := l.archived[oldName], l.archived[newName]
+	rename := func() {
+		delete(l.projectTasks, oldName)
+		l.projectTasks[newName] = tasks
+		if len(archived) > 0 {
+			delete(l.archived, oldName)
+			l.archived[newName] = append(append([]*Task(nil), archivedUnderNew...), archived...)
+		}
+	}
+	rename()
+	l.recordUndo(
+		func() {
+			delete(l.projectTasks, newName)
+			l.projectTasks[oldName] = tasks
+			if len(archived) > 0 {
+				l.archived[oldName] = archived
+				l.archived[newName] = archivedUnderNew
+				if len(archivedUnderNew) == 0 {
+					delete(l.archived, newName)
+				}
+			}
+		},
+		rename,
+	)
+	if l.currentProject == oldName {
+		l.currentProject = newName
+	}
+	fmt.Fprintf(l.out, "Renamed project \"%s\" to \"%s\".\n", oldName, newName)
+}
+
+// deleteProject removes a project. A project still holding tasks is only removed,
+// along with its tasks, when forced.
+func (l *TaskList) deleteProject(name string, force bool) {
+	project, ok := l.findProject(name)
+	if !ok {
+		l.fail(codeProjectNotFound, "Could not find a project with the name \"%s\".", name)
+		return
+	}
+	name = project
+	tasks := append([]*Task(nil), l.projectTasks[name]...)
+	if len(tasks) > 0 && !force {
+		l.fail(codeProjectNotEmpty, "Project \"%s\" still has %s, use --force to delete them too.", name, countOf(len(tasks), "task"))
+		return
+	}
+	for _, task := range tasks {
+		l.discardTask(task)
+	}
+	delete(l.projectTasks, name)
+	l.recordUndo(
+		func() { l.projectTasks[name] = make([]*Task, 0, len(tasks)) },
+		func() { delete(l.projectTasks, name) },
+	)
+	if l.currentProject == name {
+		l.currentProject = ""
+	}
+	if len(tasks) == 0 {
+		fmt.Fprintf(l.out, "Deleted project \"%s\".\n", name)
+		return
+	}
+	fmt.Fprintf(l.out, "Deleted project \"%s\" and its %s:\n", name, countOf(len(tasks), "task"))
+	table := l.newTaskTable()
+	for _, task := range tasks {
+		table.Add(task)
+	}
+	table.Flush(l.out)
+}
+
+// deleteTask removes a task from its project.
+func (l *TaskList) deleteTask(idString string) {
 	task, err := l.getTaskBy(idString)
 	if err != nil {
 		return
 	}
-	task.done = done
+	l.discardTask(task)
+	fmt.Fprintf(l.out, "Deleted task %s: %s\n", task.GetID(), task.GetDescription())
+	l.notify(eventDeleted, fmt.Sprintf("Task %s deleted: %s", task.GetID(), task.GetDescription()))
+}
+
+// discardTask removes a task from its project, along with the references its subtasks
+// and the tasks it blocks hold to it.
+func (l *TaskList) discardTask(task *Task) {
+	l.recordDeletion(task)
+	l.removeTask(task)
+	l.orphanSubtasks(task)
+	l.forgetBlocker(task)
+}
+
+// move files a task under another project, keeping its ID, status and deadline.
+func (l *TaskList) move(idString, projectName string) {
+	task, err := l.getTaskBy(idString)
+	if err != nil {
+		return
+	}
+	project, ok := l.findProject(projectName)
+	if !ok {
+		l.fail(codeProjectNotFound, "Could not find a project with the name \"%s\".", projectName)
+		return
+	}
+	projectName = project
+	tasks := l.projectTasks[projectName]
+	from := l.removeTask(task)
+	if from == projectName {
+		tasks = l.projectTasks[projectName]
+	}
+	l.projectTasks[projectName] = append(tasks, task)
+	fmt.Fprintf(l.out, "Moved task %s from \"%s\" to \"%s\".\n", task.GetID(), from, projectName)
+}
+
+// removeTask takes a task out of its project, returning the name of the project.
+func (l *TaskList) removeTask(task *Task) string {
+	for projectName, tasks := range l.projectTasks {
+		for i, t := range tasks {
+			if t == task {
+				l.projectTasks[projectName] = append(tasks[:i], tasks[i+1:]...)
+				return projectName
+			}
+		}
+	}
+	return ""
 }
 
 func (l *TaskList) getTaskBy(idString string) (*Task, error) {
 	id, err := NewIdentifier(idString)
 	if err != nil {
-		fmt.Fprintf(l.out, "Invalid ID \"%s\".\n", idString)
+		l.fail(codeInvalidID, "Invalid ID \"%s\".", idString)
 		return nil, err
 	}
+	if task := l.findTask(id); task != nil {
+		return task, nil
+	}
+
+	l.fail(codeTaskNotFound, "Task with ID \"%s\" not found.%s", id, l.suggestTask(id))
+	return nil, TaskNotFoundErr
+}
 
+// findTask returns the task with the given ID, or nil when there is none.
+func (l *TaskList) findTask(id identifier) *Task {
 	for _, tasks := range l.projectTasks {
 		for _, task := range tasks {
 			if task.GetID() == id {
-				return task, nil
+				return task
 			}
 		}
 	}
-
-	fmt.Fprintf(l.out, "Task with ID \"%d\" not found.\n", id)
-	return nil, TaskNotFoundErr
+	return nil
 }
 
-func (l *TaskList) nextID() int64 {
-	l.lastID++
-	return l.lastID
+// nextID returns the next number in sequence not already chosen as an ID by the user.
+func (l *TaskList) nextID() identifier {
+	for {
+		l.lastID++
+		id := identifier(strconv.FormatInt(l.lastID, 10))
+		if !l.chosenIDs[id] {
+			return id
+		}
+	}
 }
 
 func (l *TaskList) deadline(id string, deadlineString string) {
-	deadline, err := NewDeadline(deadlineString)
+	deadline, err := l.parseDeadline(deadlineString)
 	if err != nil {
+		l.fail(codeInvalidDate, "Invalid date \"%s\".", deadlineString)
 		return
 	}
 
@@ -251,5 +895,12 @@ func (l *TaskList) deadline(id string, deadlineString string) {
 		return
 	}
 
-	task.deadline = deadline
+	previous, history := task.deadline, task.previousDeadlines
+	task.SetDeadline(deadline)
+	next, nextHistory := task.deadline, task.previousDeadlines
+	l.recordUndo(
+		func() { task.deadline, task.previousDeadlines = previous, history },
+		func() { task.deadline, task.previousDeadlines = next, nextHistory },
+	)
+	l.warnDeadlineLoad(task)
 }
@@ -0,0 +1,2409 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestTaskList creates a TaskList writing into a buffer, with a clock frozen at the given date.
+func newTestTaskList(now time.Time) (*TaskList, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	l := NewTaskList(strings.NewReader(""), out)
+	l.now = func() time.Time { return now }
+	return l, out
+}
+
+// executeAll runs the given commands, failing the test on the first error.
+func executeAll(t *testing.T, l *TaskList, cmds ...string) {
+	t.Helper()
+	for _, cmd := range cmds {
+		if err := l.execute(cmd); err != nil {
+			t.Fatalf("%q: unexpected error: %v", cmd, err)
+		}
+	}
+}
+
+// assertOutput compares the buffered output with the expected lines, then empties the buffer.
+func assertOutput(t *testing.T, out *bytes.Buffer, lines ...string) {
+	t.Helper()
+	expected := strings.Join(lines, "\n") + "\n"
+	if actual := out.String(); actual != expected {
+		t.Errorf("unexpected output\nexpected:\n%s\ngot:\n%s", expected, actual)
+	}
+	out.Reset()
+}
+
+func TestViewCalendar(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"add task secrets Buy a cat.",
+		"deadline 1 20240603",
+		"deadline 2 20240603",
+		"deadline 3 20240630",
+		"check 3",
+	)
+	out.Reset()
+
+	executeAll(t, l, "view calendar")
+	assertOutput(t, out,
+		"June 2024",
+		"Mo     Tu     We     Th     Fr     Sa     Su",
+		"                                    1      2",
+		" 3[2]   4      5      6      7      8      9",
+		"10     11     12     13     14     15     16",
+		"17     18     19     20     21     22     23",
+		"24     25     26     27     28     29     30",
+		"",
+	)
+
+	executeAll(t, l, "view calendar 202407")
+	if !strings.HasPrefix(out.String(), "July 2024\n") {
+		t.Errorf("expected July 2024 calendar, got:\n%s", out.String())
+	}
+	out.Reset()
+
+	executeAll(t, l, "calendar")
+	if !strings.HasPrefix(out.String(), "June 2024\n") || !strings.Contains(out.String(), " 3[2]") {
+		t.Errorf("expected the June 2024 calendar from the calendar command, got:\n%s", out.String())
+	}
+}
+
+func TestAgenda(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add project training",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"add task training SOLID",
+		"add task training Outside-In TDD",
+		"add task training Primitive Obsession",
+		"deadline 1 20240612",
+		"deadline 2 20240601",
+		"deadline 3 20240610",
+		"deadline 4 20240620",
+		"deadline 5 20240605",
+		"check 5",
+	)
+	out.Reset()
+
+	executeAll(t, l, "agenda")
+	assertOutput(t, out,
+		"Overdue",
+		"    [ ] 2: (20240601) Destroy all humans. [secrets]",
+		"",
+		"Today",
+		"    [ ] 3: (20240610) SOLID [training]",
+		"",
+		"Next 3 days",
+		"    [ ] 1: (20240612) Eat more donuts. [secrets]",
+		"",
+	)
+}
+
+func TestShowProjectProgress(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project shopping",
+		"add project empty",
+		"add task shopping Milk",
+		"add task shopping Eggs",
+		"add task shopping Bread",
+		"check 1",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"empty (0/0, 0%)",
+		"",
+		"shopping (1/3, 33%)",
+		"    [X] 1: Milk",
+		"    [ ] 2: Eggs",
+		"    [ ] 3: Bread",
+		"",
+	)
+
+	executeAll(t, l, "set bars on", "show")
+	assertOutput(t, out,
+		"empty (0/0, 0%) [----------]",
+		"",
+		"shopping (1/3, 33%) [###-------]",
+		"    [X] 1: Milk",
+		"    [ ] 2: Eggs",
+		"    [ ] 3: Bread",
+		"",
+	)
+}
+
+func TestShowSummaryFooter(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"add task secrets Buy a cat.",
+		"add task secrets Feed the cat.",
+		"deadline 1 20240601",
+		"deadline 2 20240610",
+		"deadline 3 20240601",
+		"check 3",
+		"set footer on",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"secrets (1/4, 25%)",
+		"    [ ] 1: (20240601) Eat more donuts.",
+		"    [ ] 2: (20240610) Destroy all humans.",
+		"    [X] 3: (20240601) Buy a cat.",
+		"    [ ] 4: Feed the cat.",
+		"",
+		"Open: 3, done: 1, overdue: 1, due today: 1",
+	)
+}
+
+func TestShowColumns(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"deadline 2 20240601",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show --columns deadline,description")
+	assertOutput(t, out,
+		"secrets (0/2, 0%)",
+		"               Eat more donuts.",
+		"    (20240601) Destroy all humans.",
+		"",
+	)
+
+	executeAll(t, l, "show --columns id,title")
+	assertOutput(t, out, `Could not show tasks: unknown column "title".`)
+}
+
+func TestDisplayModes(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts, then destroy all humans.",
+		"deadline 1 20240610",
+	)
+	out.Reset()
+
+	executeAll(t, l, "set display compact", "today")
+	assertOutput(t, out,
+		"secrets",
+		"    [ ] 1: Eat more donuts, then destr...",
+		"",
+	)
+
+	executeAll(t, l, "set display detailed", "today")
+	assertOutput(t, out,
+		"secrets",
+		"    [ ] 1: (20240610) Eat more donuts, then destroy all humans.",
+		"        created: 2024-06-10 09:00",
+		"",
+	)
+
+	executeAll(t, l, "set display verbose")
+	assertOutput(t, out, `Could not change setting: invalid display "verbose", expected compact, normal or detailed.`)
+}
+
+func TestInvalidSettingKeepsValue(t *testing.T) {
+	for _, tc := range []struct {
+		key, value, invalid string
+	}{
+		{key: "display", value: "compact", invalid: "verbose"},
+		{key: "icons", value: "on", invalid: "sometimes"},
+		{key: "aging", value: "3,10", invalid: "x"},
+		{key: "priority-aging", value: "7,14", invalid: "soon"},
+		{key: "notify", value: "done=desktop", invalid: "done=pigeon"},
+	} {
+		s := defaultSettings()
+		if err := s.Set(tc.key, tc.value); err != nil {
+			t.Fatalf("set %s %s: unexpected error: %v", tc.key, tc.value, err)
+		}
+		if err := s.Set(tc.key, tc.invalid); err == nil {
+			t.Errorf("set %s %s should fail", tc.key, tc.invalid)
+		}
+		if got := s.Get(tc.key); got != tc.value {
+			t.Errorf("set %s %s changed the setting to %q, want %q kept", tc.key, tc.invalid, got, tc.value)
+		}
+	}
+}
+
+func TestStatusIcons(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"add task secrets Buy a cat.",
+		"add task secrets Feed the cat.",
+		"deadline 1 20240601",
+		"deadline 2 20240610",
+		"check 3",
+	)
+	out.Reset()
+
+	executeAll(t, l, "set icons auto", "show")
+	assertOutput(t, out,
+		"secrets (1/4, 25%)",
+		"    [ ] 1: (20240601) Eat more donuts.",
+		"    [ ] 2: (20240610) Destroy all humans.",
+		"    [X] 3: Buy a cat.",
+		"    [ ] 4: Feed the cat.",
+		"",
+	)
+
+	executeAll(t, l, "set icons on", "show")
+	assertOutput(t, out,
+		"secrets (1/4, 25%)",
+		"    🔥 1: (20240601) Eat more donuts.",
+		"    ⏰ 2: (20240610) Destroy all humans.",
+		"    ✅ 3: Buy a cat.",
+		"    ⬜ 4: Feed the cat.",
+		"",
+	)
+}
+
+func TestPromptTemplateFromConfig(t *testing.T) {
+	l, _ := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	config := strings.NewReader(`
+# prompt showing the current context
+workspace = home
+prompt = "{{.Workspace}}:{{.Project}} ({{.OpenCount}})> "
+`)
+	if err := l.LoadConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"check 1",
+	)
+
+	if actual := l.prompt(); actual != "home:secrets (1)> " {
+		t.Errorf("expected prompt \"home:secrets (1)> \", got %q", actual)
+	}
+}
+
+func TestLoadConfigReportsInvalidLines(t *testing.T) {
+	l, _ := newTestTaskList(time.Now())
+	err := l.LoadConfig(strings.NewReader("bars = on\nicons = maybe\n"))
+	expected := `config line 2: invalid icons "maybe", expected on, off or auto`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestShowColumnsAlignsWideCharacters(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project 買い物",
+		"add task 買い物 牛乳",
+		"add task 買い物 Eggs",
+		"add task 買い物 🔥 Bread",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show --columns description,status")
+	assertOutput(t, out,
+		"買い物 (0/3, 0%)",
+		"    牛乳     [ ]",
+		"    Eggs     [ ]",
+		"    🔥 Bread [ ]",
+		"",
+	)
+}
+
+func TestRunBatchReportsFailingLines(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"deadline",
+		"show",
+		"add",
+		"chek 1",
+		"check 7",
+		"add task training SOLID",
+		"quit",
+		"show",
+	}, "\n"))
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+
+	err := NewTaskList(in, out).RunBatch(errOut)
+
+	if err == nil || err.Error() != "5 of 8 commands failed" {
+		t.Errorf("expected 5 of 8 commands to fail, got %v", err)
+	}
+	assertOutput(t, errOut,
+		"line 3: could not execute deadline. Usage: deadline <taskId> <dateAsString>",
+		"line 5: could not execute add, it requires at least 2 parameters",
+		`line 6: Unknown command "chek". Did you mean "check"?`,
+		`line 7: Task with ID "7" not found.`,
+		`line 8: Could not find a project with the name "training".`,
+	)
+	assertOutput(t, out,
+		"secrets (0/1, 0%)",
+		"    [ ] 1: Eat more donuts.",
+		"",
+	)
+}
+
+func TestJSONErrors(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		"add project secrets",
+		"add task training SOLID",
+		"chek 1",
+		"deadline",
+		"add task secrets SOLID",
+		"deadline 1 someday",
+	}, "\n"))
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	l := NewTaskList(in, out)
+	l.EnableJSONErrors(errOut)
+
+	l.RunBatch(errOut)
+
+	assertOutput(t, errOut,
+		`{"code":"project_not_found","message":"Could not find a project with the name \"training\".","command":"add task training SOLID","line":2}`,
+		`{"code":"unknown_command","message":"Unknown command \"chek\". Did you mean \"check\"?","command":"chek 1","line":3}`,
+		`{"code":"usage","message":"could not execute deadline. Usage: deadline <taskId> <dateAsString>","command":"deadline","line":4}`,
+		`{"code":"invalid_date","message":"Invalid date \"someday\".","command":"deadline 1 someday","line":6}`,
+	)
+	if out.Len() != 0 {
+		t.Errorf("expected no error prose on the output, got:\n%s", out.String())
+	}
+}
+
+func TestBrief(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "brief")
+	assertOutput(t, out, "You have nothing due today.")
+
+	executeAll(t, l,
+		"add project home",
+		"add project shopping",
+		"add project work",
+		"add task home Water the plants",
+		"add task shopping Milk",
+		"add task work Review PR",
+		"add task work Write report",
+		"add task work Plan sprint",
+		"deadline 1 20240610",
+		"deadline 2 20240608",
+		"deadline 3 20240610",
+		"deadline 4 20240610",
+		"deadline 5 20240612",
+	)
+	out.Reset()
+
+	executeAll(t, l, "brief")
+	assertOutput(t, out, "You have 3 tasks due today, in projects home and work. "+
+		"1 task overdue, in project shopping. 1 more task due in the next 3 days.")
+}
+
+func TestDeadlineLoadWarning(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 7, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"set max-per-day 1",
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"add task secrets Buy a cat.",
+		"deadline 1 20240610",
+		"deadline 2 20240611",
+	)
+	out.Reset()
+
+	executeAll(t, l, "deadline 3 20240610")
+	assertOutput(t, out,
+		"Warning: 2 tasks are due on 2024-06-10, more than the 1 per day limit.",
+		"Nearest lighter day: 2024-06-12.",
+	)
+}
+
+func TestRollover(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"add task secrets Buy a cat.",
+		"deadline 1 20240607",
+		"deadline 2 20240607",
+		"deadline 3 20240612",
+		"check 2",
+	)
+	out.Reset()
+
+	executeAll(t, l, "rollover")
+	assertOutput(t, out, "Rolled over 1 task to today.")
+
+	executeAll(t, l, "set auto-rollover on", "show")
+	out.Reset()
+	now = now.AddDate(0, 0, 3)
+	executeAll(t, l, "set display detailed", "show")
+	assertOutput(t, out,
+		"Rolled over 2 overdue tasks to today.",
+		"secrets (1/3, 33%)",
+		"    [ ] 1: (20240613) Eat more donuts.",
+		"        created: 2024-06-10 09:00",
+		"        rolled-over: 2 times",
+		"    [X] 2: (20240607) Destroy all humans.",
+		"        created: 2024-06-10 09:00",
+		"    [ ] 3: (20240613) Buy a cat.",
+		"        created: 2024-06-10 09:00",
+		"        rolled-over: once",
+		"",
+	)
+}
+
+func TestYesterday(t *testing.T) {
+	now := time.Date(2024, time.June, 9, 18, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"add task secrets Buy a cat.",
+		"add task secrets Feed the cat.",
+		"deadline 1 20240609",
+		"deadline 2 20240609",
+		"deadline 3 20240609",
+		"check 3",
+		"deadline 2 20240612",
+		"check 4",
+	)
+	now = now.AddDate(0, 0, 1)
+	out.Reset()
+
+	executeAll(t, l, "yesterday")
+	assertOutput(t, out,
+		"Completed yesterday",
+		"    [X] 3: (20240609) Buy a cat. [secrets]",
+		"    [X] 4: Feed the cat. [secrets]",
+		"",
+		"Slipped from yesterday",
+		"    [ ] 1: (20240609) Eat more donuts. [secrets]",
+		"    [ ] 2: (20240612) Destroy all humans. [secrets]",
+		"",
+	)
+}
+
+func TestGoals(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project v2",
+		"add task v2 Write the changelog",
+		"add task v2 Tag the release",
+		"add task v2 Announce it",
+		"check 1",
+	)
+	out.Reset()
+
+	executeAll(t, l,
+		`goal add "Ship v2" --by 2024-06-30`,
+		"goal add Learn Go",
+		"goal link 1 1",
+		"goal link 2 1",
+		"goal link 3 7",
+	)
+	assertOutput(t, out,
+		"Added goal 1: Ship v2",
+		"Added goal 2: Learn Go",
+		`Linked task 1 to goal "Ship v2".`,
+		`Linked task 2 to goal "Ship v2".`,
+		`Goal with ID "7" not found.`,
+	)
+
+	executeAll(t, l, "goals")
+	assertOutput(t, out,
+		"1: Ship v2 (1/2, 50%), 20 days left",
+		"2: Learn Go (0/0, 0%)",
+	)
+}
+
+func TestGoalDaysRemaining(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 23, 30, 0, 0, time.Local))
+	executeAll(t, l,
+		"goal add Tomorrow --by 2024-06-11",
+		"goal add Today --by 2024-06-10",
+		"goal add Yesterday --by 2024-06-09",
+		"goal add Last week --by 2024-06-03",
+	)
+	out.Reset()
+
+	executeAll(t, l, "goals")
+	assertOutput(t, out,
+		"1: Tomorrow (0/0, 0%), 1 day left",
+		"2: Today (0/0, 0%), due today",
+		"3: Yesterday (0/0, 0%), 1 day overdue",
+		"4: Last week (0/0, 0%), 7 days overdue",
+	)
+}
+
+func TestHabitStreaks(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l,
+		"habit add Stretch daily",
+		"habit add Review goals weekly",
+		"habit done 1",
+		"habit done 2",
+	)
+	now = now.AddDate(0, 0, 1)
+	executeAll(t, l, "habit done 1")
+	now = now.AddDate(0, 0, 1)
+	out.Reset()
+
+	executeAll(t, l, "habits")
+	assertOutput(t, out,
+		"    [ ] 1: Stretch (daily, streak 2)",
+		"    [X] 2: Review goals (weekly, streak 1)",
+	)
+
+	now = now.AddDate(0, 0, 1)
+	executeAll(t, l, "habit done 1")
+	assertOutput(t, out, "Habit 1 done, streak 1.")
+}
+
+func TestViewContext(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project chores",
+		"add project work",
+		"add task chores Water the plants",
+		"add task chores Buy light bulbs",
+		"add task work Print the slides",
+		"context 1 @home",
+		"context 2 @errands @Home",
+		"context 3 @office",
+	)
+	out.Reset()
+
+	executeAll(t, l, "view @home")
+	assertOutput(t, out,
+		"chores",
+		"    [ ] 1: Water the plants",
+		"    [ ] 2: Buy light bulbs",
+		"",
+	)
+
+	executeAll(t, l, "context 2 none", "view @errands")
+	assertOutput(t, out, "No matching tasks.")
+
+	executeAll(t, l, "show --columns id,contexts,description")
+	assertOutput(t, out,
+		"chores (0/2, 0%)",
+		"    1: @home Water the plants",
+		"    2:       Buy light bulbs",
+		"",
+		"work (0/1, 0%)",
+		"    3: @office Print the slides",
+		"",
+	)
+}
+
+func TestViewQuick(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project chores",
+		"add project work",
+		"add task chores Water the plants",
+		"add task chores Repaint the kitchen",
+		"add task work Reply to Ada",
+		"add task work Book the room",
+		"size 1 s",
+		"size 2 L",
+		"size 3 S",
+		"size 4 S",
+		"check 4",
+	)
+	out.Reset()
+
+	executeAll(t, l, "view quick")
+	assertOutput(t, out,
+		"chores",
+		"    [ ] 1: Water the plants",
+		"",
+		"work",
+		"    [ ] 3: Reply to Ada",
+		"",
+	)
+}
+
+func TestShowAgeMarkers(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l, "add project secrets", "add task secrets Eat more donuts.")
+	now = now.AddDate(0, 0, 10)
+	executeAll(t, l, "add task secrets Destroy all humans.", "add task secrets Buy a cat.", "check 3")
+	now = now.AddDate(0, 0, 10)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"secrets (1/3, 33%)",
+		"    [ ] 1: Eat more donuts. ··",
+		"    [ ] 2: Destroy all humans. ·",
+		"    [X] 3: Buy a cat.",
+		"",
+	)
+
+	executeAll(t, l, "set aging 7,14,20", "show")
+	assertOutput(t, out,
+		"secrets (1/3, 33%)",
+		"    [ ] 1: Eat more donuts. ··",
+		"    [ ] 2: Destroy all humans. ·",
+		"    [X] 3: Buy a cat.",
+		"",
+	)
+
+	executeAll(t, l, "set weekend none", "show")
+	assertOutput(t, out,
+		"secrets (1/3, 33%)",
+		"    [ ] 1: Eat more donuts. ···",
+		"    [ ] 2: Destroy all humans. ·",
+		"    [X] 3: Buy a cat.",
+		"",
+	)
+
+	executeAll(t, l, "set aging off", "show")
+	assertOutput(t, out,
+		"secrets (1/3, 33%)",
+		"    [ ] 1: Eat more donuts.",
+		"    [ ] 2: Destroy all humans.",
+		"    [X] 3: Buy a cat.",
+		"",
+	)
+}
+
+func TestAddWarnsAboutDuplicates(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	l.in = strings.NewReader("n\ny\n")
+	executeAll(t, l,
+		"add project shopping",
+		"add task shopping Buy milk.",
+		"add task shopping Renew the passport",
+	)
+	out.Reset()
+
+	executeAll(t, l, "add task shopping buy  MILK")
+	assertOutput(t, out,
+		`Task 1 "Buy milk." looks the same. Add anyway? [y/N] Task not added.`,
+	)
+
+	executeAll(t, l,
+		"add task shopping Renew teh pasport",
+		"add task shopping Buy silk",
+		"add task shopping Buy bread",
+		"show",
+	)
+	assertOutput(t, out,
+		`Task 2 "Renew the passport" looks the same. Add anyway? [y/N] shopping (0/5, 0%)`,
+		"    [ ] 1: Buy milk.",
+		"    [ ] 2: Renew the passport",
+		"    [ ] 3: Renew teh pasport",
+		"    [ ] 4: Buy silk",
+		"    [ ] 5: Buy bread",
+		"",
+	)
+}
+
+func TestFindDuplicate(t *testing.T) {
+	l, _ := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Fix bug 12 in the parser",
+		"add task work Buy milk",
+		"add task work Prepare the quarterly report",
+	)
+	for description, want := range map[string]bool{
+		"fix bug 12 in the parser!":   true,
+		"Fix bug 13 in the parser":    false,
+		"Buy silk":                    false,
+		"Prepare the quartely report": true,
+		"":                            false,
+	} {
+		if _, got := l.findDuplicate("work", description); got != want {
+			t.Errorf("findDuplicate(%q) = %v, want %v", description, got, want)
+		}
+	}
+}
+
+func TestRunBatchFailsDeclinedDuplicates(t *testing.T) {
+	in := strings.NewReader("add project shopping\nadd task shopping Buy milk\nadd task shopping buy milk\n")
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+
+	err := NewTaskList(in, out).RunBatch(errOut)
+
+	if err == nil || err.Error() != "1 of 3 commands failed" {
+		t.Errorf("expected the declined add to fail, got %v", err)
+	}
+	assertOutput(t, errOut, "line 3: Task not added.")
+}
+
+func TestNormalizeDescriptions(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	config := strings.NewReader(`
+normalize = trim,collapse,capitalize
+replace = teh => the
+replace = w/ => with
+`)
+	if err := l.LoadConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	executeAll(t, l,
+		"add project chores",
+		`add task chores "  teh   plants need water w/ fertilizer  "`,
+		"add task chores tehran trip",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"chores (0/2, 0%)",
+		"    [ ] 1: The plants need water with fertilizer",
+		"    [ ] 2: Tehran trip",
+		"",
+	)
+}
+
+func TestApplyToQuery(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Review budget",
+		"add task home Review insurance",
+		"add task work Write report",
+		"check 2",
+	)
+	out.Reset()
+
+	executeAll(t, l, `apply --dry-run "project:work pending review" deadline 20240612`)
+	assertOutput(t, out,
+		`Would apply "deadline 20240612" to 1 task:`,
+		"    [ ] 1: Review PR [work]",
+		"",
+	)
+
+	executeAll(t, l, `apply "review -done" context @desk`)
+	assertOutput(t, out,
+		`Applied "context @desk" to 2 tasks:`,
+		"    [ ] 3: Review insurance [home]",
+		"    [ ] 1: Review PR [work]",
+		"",
+	)
+
+	executeAll(t, l, `apply review context @desk`)
+	assertOutput(t, out,
+		`Applied "context @desk" to 1 task:`,
+		"    [X] 2: Review budget [work]",
+		"",
+	)
+
+	executeAll(t, l, "add subtask 1 Read the diff", `apply "review -done" check`)
+	assertOutput(t, out,
+		"Task 1 still has open subtasks, check them first.",
+		`Applied "check" to no task, as it failed for task 1.`,
+	)
+
+	executeAll(t, l, `apply "project:home" check`)
+	assertOutput(t, out,
+		`Applied "check" to 1 task:`,
+		"    [X] 3: Review insurance [home]",
+		"",
+	)
+
+	executeAll(t, l, `apply overdue check`)
+	assertOutput(t, out, "No task matches the query.")
+}
+
+func TestDeleteTask(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Review PR",
+		"add task work Write report",
+	)
+	out.Reset()
+
+	executeAll(t, l, "delete 1", "delete 1", "delete #1", "show")
+	assertOutput(t, out,
+		"Deleted task 1: Review PR",
+		`Task with ID "1" not found.`,
+		`Invalid ID "#1".`,
+		"work (0/1, 0%)",
+		"    [ ] 2: Write report",
+		"",
+	)
+}
+
+func TestSeedDemo(t *testing.T) {
+	l, out := newTestTaskList(time.Now())
+	if err := l.SeedDemo(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"home (0/2, 0%)",
+		"    [ ] 4: (20240614) Renew the insurance",
+		"    [ ] 5: Fix the bike",
+		"",
+		"workshop (1/3, 33%)",
+		"    [X] 1: (20240607) Book the room",
+		"    [ ] 2: (20240610) Send the invitations",
+		"    [ ] 3: (20240612) Print the handouts",
+		"",
+	)
+
+	executeAll(t, l, "today")
+	assertOutput(t, out,
+		"home",
+		"    [ ] 5: Fix the bike",
+		"",
+		"workshop",
+		"    [X] 1: (20240607) Book the room",
+		"    [ ] 2: (20240610) Send the invitations",
+		"",
+	)
+
+	fixtures := filepath.Join(t.TempDir(), "fixtures")
+	if err := os.WriteFile(fixtures, []byte("# sample\nadd project demo\nadd\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	l, _ = newTestTaskList(time.Now())
+	if err := l.SeedDemo(fixtures); err == nil || !strings.HasPrefix(err.Error(), "fixtures line 3:") {
+		t.Errorf("expected an error on fixtures line 3, got %v", err)
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project empty",
+		"add project work",
+		"add task work Review PR",
+		"add task work Write report",
+	)
+	out.Reset()
+
+	executeAll(t, l, "delete project empty", "delete project work", "delete project work --force", "delete project work", "show")
+	assertOutput(t, out,
+		`Deleted project "empty".`,
+		`Project "work" still has 2 tasks, use --force to delete them too.`,
+		`Deleted project "work" and its 2 tasks:`,
+		"    [ ] 1: Review PR",
+		"    [ ] 2: Write report",
+		`Could not find a project with the name "work".`,
+	)
+}
+
+func TestDeleteProjectReleasesBlockedTasks(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task home Fix the bike",
+		"depends 2 on 1",
+		"delete project work --force",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show", "undo", "show")
+	assertOutput(t, out,
+		"home (0/1, 0%)",
+		"    [ ] 2: Fix the bike",
+		"",
+		`Undid "delete project work --force".`,
+		"home (0/1, 0%)",
+		"    [ ] 2: Fix the bike blocked by 1",
+		"",
+		"work (0/1, 0%)",
+		"    [ ] 1: Review PR",
+		"",
+	)
+}
+
+func TestTutorial(t *testing.T) {
+	var out bytes.Buffer
+	l := NewTaskList(strings.NewReader("show\nadd project work\nadd task work Review PR\ndeadline 1 2024\ndeadline 1 20240612\ncheck 1\nagenda\n"), &out)
+	l.now = func() time.Time { return time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local) }
+
+	executeAll(t, l, "tutorial")
+	assertOutput(t, &out,
+		"Step 1 of 5. Tasks are grouped by project. Create one with: add project <name>",
+		`tutorial> Please type a command starting with "add", or quit to leave the tutorial.`,
+		"tutorial> Step 2 of 5. Add a task to your project with: add task <project name> <description>",
+		"tutorial> Step 3 of 5. Give the task a deadline with: deadline <task ID> <YYYYMMDD>",
+		"tutorial> Not quite, try again.",
+		"tutorial> Step 4 of 5. Mark the task as done with: check <task ID>",
+		"tutorial> Step 5 of 5. Look at your tasks with one of the views: show, today, agenda or view calendar",
+		"tutorial> Nothing on the agenda.",
+		"Well done! Type help to see every command.",
+	)
+}
+
+func TestCustomTaskIDs(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work --id review-pr Review PR",
+		"add task work --id 2 Write report",
+		"add task work Plan sprint",
+		"check review-pr",
+	)
+	out.Reset()
+
+	executeAll(t, l, "add task work --id review-pr Again", "add task work --id no! Again", "show")
+	assertOutput(t, out,
+		`ID "review-pr" is already used.`,
+		`Invalid ID "no!", use only letters, digits, - and _.`,
+		"work (1/3, 33%)",
+		"    [X] review-pr: Review PR",
+		"    [ ] 2: Write report",
+		"    [ ] 1: Plan sprint",
+		"",
+	)
+}
+
+func TestTelemetryIsOptIn(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project work", "telemetry show")
+	assertOutput(t, out, "Telemetry is off, nothing is recorded. Turn it on with: set telemetry on")
+
+	executeAll(t, l, "set telemetry on", "add task work Review PR", "check 7", "frobnicate secrets", "telemetry show")
+	assertOutput(t, out,
+		`Task with ID "7" not found.`,
+		`Unknown command "frobnicate".`,
+		"{",
+		`  "commands": {`,
+		`    "add": 1,`,
+		`    "check": 1,`,
+		`    "other": 1,`,
+		`    "set": 1`,
+		"  },",
+		`  "failures": {`,
+		`    "check": 1,`,
+		`    "other": 1`,
+		"  }",
+		"}",
+	)
+}
+
+func TestViewByDate(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l, "add project work", "add project home", "add task work Review PR")
+	now = now.AddDate(0, 0, 1)
+	executeAll(t, l, "add task home Fix the bike", "add task work Write report")
+	out.Reset()
+
+	executeAll(t, l, "view by date")
+	assertOutput(t, out,
+		"2024-06-10",
+		"    [ ] 1: Review PR [work]",
+		"",
+		"2024-06-11",
+		"    [ ] 2: Fix the bike [home]",
+		"    [ ] 3: Write report [work]",
+		"",
+	)
+}
+
+func TestCommandPanicKeepsSession(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	l.crashLog = filepath.Join(t.TempDir(), "crash.log")
+	commands["explode"] = func(l *TaskList, args []string) error { return l.add(args[:1]) }
+	defer delete(commands, "explode")
+	executeAll(t, l, "add project work")
+	out.Reset()
+
+	err := l.execute("explode 1")
+	if want := `Command "explode 1" failed unexpectedly, details were written to ` + l.crashLog + "."; err == nil || err.Error() != want {
+		t.Errorf("expected the panic to fail the command with %q, got %v", want, err)
+	}
+	executeAll(t, l, "add task work Review PR", "show")
+	assertOutput(t, out,
+		"work (0/1, 0%)",
+		"    [ ] 1: Review PR",
+		"",
+	)
+	crash, err := os.ReadFile(l.crashLog)
+	if err != nil || !strings.Contains(string(crash), "index out of range") {
+		t.Errorf("expected the panic in the crash log, got %q (%v)", crash, err)
+	}
+}
+
+func TestShortArgumentsAreUsageErrors(t *testing.T) {
+	l, _ := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	l.crashLog = filepath.Join(t.TempDir(), "crash.log")
+	executeAll(t, l, "add project work", "add task work Review PR")
+
+	for _, cmd := range []string{"add project", "add task", "add task work", "add task work --id review", "deadline 1"} {
+		if err := l.execute(cmd); err == nil || strings.Contains(err.Error(), "unexpectedly") {
+			t.Errorf("%q: expected a usage error, got %v", cmd, err)
+		}
+	}
+	if _, err := os.Stat(l.crashLog); err == nil {
+		t.Errorf("expected no crash to be logged")
+	}
+}
+
+func TestViewByDeadline(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task home Fix the bike",
+		"add task work Write report",
+		"deadline 1 20240614",
+		"deadline 2 20240612",
+		"deadline 3 20240614",
+		"add task home Renew the insurance",
+	)
+	out.Reset()
+
+	executeAll(t, l, "view by deadline")
+	assertOutput(t, out,
+		"2024-06-12",
+		"    [ ] 2: (20240612) Fix the bike [home]",
+		"",
+		"2024-06-14",
+		"    [ ] 1: (20240614) Review PR [work]",
+		"    [ ] 3: (20240614) Write report [work]",
+		"",
+		"No deadline",
+		"    [ ] 4: Renew the insurance [home]",
+		"",
+	)
+}
+
+func TestBenchLeavesListUntouched(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "bench --tasks 20 --ops 8")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "Populated 20 tasks in ") ||
+		!strings.HasPrefix(lines[1], "Ran 8 commands in ") || !strings.HasPrefix(lines[2], "Latency: p50 ") {
+		t.Errorf("unexpected bench report:\n%s", out)
+	}
+	out.Reset()
+
+	if len(l.projectTasks) != 0 {
+		t.Errorf("expected no project, got %d", len(l.projectTasks))
+	}
+	if err := l.execute("bench --tasks"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
+
+func TestTimings(t *testing.T) {
+	l, _ := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	var timings bytes.Buffer
+	l.EnableTimings(&timings)
+	executeAll(t, l, "add project work", "show")
+
+	lines := strings.Split(strings.TrimSpace(timings.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `timings: "add project work" parse=`) ||
+		!strings.HasPrefix(lines[1], `timings: "show" parse=`) || !strings.Contains(lines[1], " run=") {
+		t.Errorf("unexpected timings:\n%s", timings.String())
+	}
+}
+
+func TestViewByProject(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project work", "add task work Review PR", "show")
+	shown := out.String()
+	out.Reset()
+
+	executeAll(t, l, "view by project")
+	if out.String() != shown {
+		t.Errorf("expected view by project to print like show\nexpected:\n%s\ngot:\n%s", shown, out)
+	}
+	if err := l.execute("view by size"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
+
+func TestEditTask(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project work", "add task work Reveiw PR")
+	out.Reset()
+
+	executeAll(t, l, "edit 1 Review PR #42", "edit 2 Nothing", "show")
+	assertOutput(t, out,
+		`Task 1: "Reveiw PR" is now "Review PR #42".`,
+		`Task with ID "2" not found.`,
+		"work (0/1, 0%)",
+		"    [ ] 1: Review PR #42",
+		"",
+	)
+}
+
+func TestRenameProject(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project work", "add project home", "add task work Review PR")
+	out.Reset()
+
+	executeAll(t, l, "rename project work home", "rename project office home", "rename project work office", "show")
+	assertOutput(t, out,
+		`A project named "home" already exists.`,
+		`Could not find a project with the name "office".`,
+		`Renamed project "work" to "office".`,
+		"home (0/0, 0%)",
+		"",
+		"office (0/1, 0%)",
+		"    [ ] 1: Review PR",
+		"",
+	)
+}
+
+func TestRenameProjectKeepsArchive(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project work", "add task work Review PR", "add task work Deploy", "check 1", "archive 1")
+	out.Reset()
+
+	executeAll(t, l, "rename project work office", "show archived", "undo", "show archived", "show")
+	assertOutput(t, out,
+		`Renamed project "work" to "office".`,
+		"office",
+		"    [X] 1: Review PR",
+		"",
+		`Undid "rename project work office".`,
+		"work",
+		"    [X] 1: Review PR",
+		"",
+		"work (0/1, 0%)",
+		"    [ ] 2: Deploy",
+		"",
+	)
+}
+
+func TestDoctor(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.UTC))
+	dir := t.TempDir()
+	config := filepath.Join(dir, "task-list.conf")
+	if err := os.WriteFile(config, []byte("icons = on\ncolour = red\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(configEnv, config)
+	l.crashLog = filepath.Join(dir, "missing", "crash.log")
+
+	executeAll(t, l, "doctor")
+	assertOutput(t, out,
+		"FAIL  config: "+config+`: config line 2: unknown setting "colour"`,
+		"      fix: correct the line in "+config+", or list valid keys with set",
+		"ok    clock: 2024-06-10 09:00 (UTC)",
+		"FAIL  crash log: open "+l.crashLog+": no such file or directory",
+		"      fix: make "+filepath.Dir(l.crashLog)+" writable, or set TMPDIR to a writable directory",
+		"2 problems found.",
+	)
+}
+
+func TestMoveTask(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Fix the bike",
+		"add task work Review PR",
+		"deadline 1 20240612",
+		"check 1",
+	)
+	out.Reset()
+
+	executeAll(t, l, "move 1 garden", "move 1 home", "show")
+	assertOutput(t, out,
+		`Could not find a project with the name "garden".`,
+		`Moved task 1 from "work" to "home".`,
+		"home (1/1, 100%)",
+		"    [X] 1: (20240612) Fix the bike",
+		"",
+		"work (0/1, 0%)",
+		"    [ ] 2: Review PR",
+		"",
+	)
+}
+
+func TestGroupedCommands(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"project add work",
+		"project add home",
+		"task add work Review PR",
+		"task add home --id bike Fix the bike",
+		"task check 1",
+		"task deadline bike 20240612",
+		"project rename home garage",
+		"task move 1 garage",
+		"project delete work",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"garage (1/2, 50%)",
+		"    [ ] bike: (20240612) Fix the bike",
+		"    [X] 1: Review PR",
+		"",
+	)
+	for _, cmd := range []string{"task", "task frobnicate 1", "project list"} {
+		if err := l.execute(cmd); err == nil {
+			t.Errorf("%q: expected a usage error", cmd)
+		}
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project home", "add task home --id 121 Buy milk", "add task home --id 450 Walk the dog")
+	out.Reset()
+
+	executeAll(t, l, "chek 12", "shwo", "frobnicate")
+	assertOutput(t, out,
+		`Unknown command "chek". Did you mean "check"?`,
+		`Unknown command "shwo". Did you mean "show"?`,
+		`Unknown command "frobnicate".`,
+	)
+	executeAll(t, l, "check 12", "check 99")
+	assertOutput(t, out,
+		`Task with ID "12" not found. Did you mean 121 "Buy milk"?`,
+		`Task with ID "99" not found.`,
+	)
+}
+
+func TestPriorities(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Write report",
+		"add task work Plan sprint",
+		"add task home Fix the bike",
+		"priority 2 high",
+		"task priority 3 low",
+		"priority 4 high",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show", "view by priority")
+	assertOutput(t, out,
+		"home (0/1, 0%)",
+		"    [ ] 4: Fix the bike",
+		"",
+		"work (0/3, 0%)",
+		"    [ ] 2: Write report",
+		"    [ ] 3: Plan sprint",
+		"    [ ] 1: Review PR",
+		"",
+		"High",
+		"    [ ] 4: Fix the bike [home]",
+		"    [ ] 2: Write report [work]",
+		"",
+		"Low",
+		"    [ ] 3: Plan sprint [work]",
+		"",
+		"No priority",
+		"    [ ] 1: Review PR [work]",
+		"",
+	)
+	if err := l.execute("priority 1 urgent"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
+
+func TestProjectNamesIgnoreCase(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project Shopping",
+		"add project SHOPPING",
+		"add task shopping Buy milk",
+		"add task SHOPPING Buy bread",
+	)
+	executeAll(t, l, "apply project:shopping size S", "show")
+	assertOutput(t, out,
+		`A project named "Shopping" already exists.`,
+		`Applied "size S" to 2 tasks:`,
+		"    [ ] 1: Buy milk [Shopping]",
+		"    [ ] 2: Buy bread [Shopping]",
+		"",
+		"Shopping (0/2, 0%)",
+		"    [ ] 1: Buy milk",
+		"    [ ] 2: Buy bread",
+		"",
+	)
+
+	executeAll(t, l, "add project C", "add project C++", "add project !!", "add project ??", "add task c++ Read the standard", "view by project")
+	assertOutput(t, out,
+		"!! (0/0, 0%)",
+		"",
+		"?? (0/0, 0%)",
+		"",
+		"C (0/0, 0%)",
+		"",
+		"C++ (0/1, 0%)",
+		"    [ ] 3: Read the standard",
+		"",
+		"Shopping (0/2, 0%)",
+		"    [ ] 1: Buy milk",
+		"    [ ] 2: Buy bread",
+		"",
+	)
+	executeAll(t, l, "delete project C", "delete project !!", "delete project ??", "delete project C++ --force")
+	out.Reset()
+
+	executeAll(t, l, "rename project shopping Groceries", "delete project GROCERIES --force")
+	assertOutput(t, out,
+		`Renamed project "Shopping" to "Groceries".`,
+		`Deleted project "Groceries" and its 2 tasks:`,
+		"    [ ] 1: Buy milk",
+		"    [ ] 2: Buy bread",
+	)
+
+	executeAll(t, l, `add project "Home Improvement"`, "add project home_improvement", "add task home-improvement Paint the fence", "show")
+	assertOutput(t, out,
+		`A project named "Home Improvement" already exists.`,
+		"Home Improvement (0/1, 0%)",
+		"    [ ] 4: Paint the fence",
+		"",
+	)
+}
+
+func TestTags(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Write report",
+		"add task home Fix the bike",
+		"tag 1 Urgent waiting",
+		"task tag 3 urgent",
+		"untag 1 waiting",
+		"apply review tag later",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show tag:urgent", "show", `apply "tag:urgent -tag:later" untag urgent`)
+	assertOutput(t, out,
+		"home",
+		"    [ ] 3: Fix the bike #urgent",
+		"",
+		"work",
+		"    [ ] 1: Review PR #later #urgent",
+		"",
+		"home (0/1, 0%)",
+		"    [ ] 3: Fix the bike #urgent",
+		"",
+		"work (0/2, 0%)",
+		"    [ ] 1: Review PR #later #urgent",
+		"    [ ] 2: Write report",
+		"",
+		`Applied "untag urgent" to 1 task:`,
+		"    [ ] 3: Fix the bike [home]",
+		"",
+	)
+	if err := l.execute("tag 1 not!valid"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project Shopping",
+		"add project work",
+		"add task Shopping Buy milk",
+		"add task work Review the shopping list feature",
+		"add task work Write report",
+	)
+	out.Reset()
+
+	executeAll(t, l, "search SHOP", "search milk shake")
+	assertOutput(t, out,
+		`2 tasks matching "SHOP":`,
+		"    [ ] 1: Buy milk [Shopping]",
+		"    [ ] 2: Review the shopping list feature [work]",
+		"",
+		`No task matches "milk shake".`,
+	)
+}
+
+func TestReplaceInDescriptions(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work OPS-12 Review PR",
+		"add task work OPS-13 Ask Bob",
+		"add task work Write report",
+	)
+	out.Reset()
+
+	l.batch = true
+	executeAll(t, l, `replace all "OPS-" "PLAT-"`)
+	assertOutput(t, out,
+		"    1: OPS-12 Review PR",
+		"       PLAT-12 Review PR",
+		"    2: OPS-13 Ask Bob",
+		"       PLAT-13 Ask Bob",
+		"Replace in 2 tasks? [y/N] n (batch mode)",
+		"Nothing replaced.",
+	)
+
+	executeAll(t, l, `replace --yes "project:work bob" "OPS-" "PLAT-"`, "show")
+	assertOutput(t, out,
+		"    2: OPS-13 Ask Bob",
+		"       PLAT-13 Ask Bob",
+		`Replaced "OPS-" with "PLAT-" in 1 task.`,
+		"work (0/3, 0%)",
+		"    [ ] 1: OPS-12 Review PR",
+		"    [ ] 2: PLAT-13 Ask Bob",
+		"    [ ] 3: Write report",
+		"",
+	)
+}
+
+func TestShowByStatus(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Write report",
+		"add task home Fix the bike",
+		"check 2",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show pending", "show done")
+	assertOutput(t, out,
+		"home",
+		"    [ ] 3: Fix the bike",
+		"",
+		"work",
+		"    [ ] 1: Review PR",
+		"",
+		"work",
+		"    [X] 2: Write report",
+		"",
+	)
+}
+
+func TestSnooze(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Review PR",
+		"add task work Write report",
+		"deadline 1 20240612",
+	)
+	out.Reset()
+
+	executeAll(t, l, "snooze 1 2d", "snooze 1 1w", "snooze 2 3bd", "show")
+	assertOutput(t, out,
+		"Snoozed task 1 until 2024-06-14.",
+		"Snoozed task 1 until 2024-06-21.",
+		"Snoozed task 2 until 2024-06-13.",
+		"work (0/2, 0%)",
+		"    [ ] 1: (20240621) Review PR",
+		"    [ ] 2: (20240613) Write report",
+		"",
+	)
+	if err := l.execute("snooze 1 tomorrow"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
+
+func TestEndSessionSummary(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Review PR",
+		"add task work Write report",
+		"add task work Plan sprint",
+		"deadline 2 20240611",
+		"check 1",
+	)
+	out.Reset()
+
+	l.EndSession()
+	if out.Len() != 0 {
+		t.Errorf("expected no summary by default, got:\n%s", out)
+	}
+
+	executeAll(t, l, "set quit-summary on")
+	l.EndSession()
+	assertOutput(t, out,
+		"Completed today",
+		"    [X] 1: Review PR [work]",
+		"",
+		"Due tomorrow",
+		"    [ ] 2: (20240611) Write report [work]",
+		"",
+	)
+}
+
+func TestRecurringTasks(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project home",
+		"add task home Pay the rent",
+		"add task home Water the plants",
+		"deadline 1 20240131",
+		"repeat 1 monthly",
+		"repeat 2 weekly",
+		"tag 2 garden",
+	)
+	out.Reset()
+
+	executeAll(t, l, "check 1", "check 1", "check 2", "show")
+	assertOutput(t, out,
+		"Next occurrence: task 3 due 2024-02-29.",
+		"Next occurrence: task 4 due 2024-06-17.",
+		"home (2/4, 50%)",
+		"    [X] 1: (20240131) Pay the rent",
+		"    [X] 2: Water the plants #garden",
+		"    [ ] 3: (20240229) Pay the rent",
+		"    [ ] 4: (20240617) Water the plants #garden",
+		"",
+	)
+	if err := l.execute("repeat 1 yearly"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
+
+func TestPriorityAging(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l, "add project work", "add task work Old chore", "add task work Old idea")
+	now = now.AddDate(0, 0, 20)
+	executeAll(t, l, "add task work Urgent fix", "priority 3 high", "priority 2 low", "check 2", "set priority-aging 7,14")
+	out.Reset()
+
+	executeAll(t, l, "view by priority")
+	assertOutput(t, out,
+		"High",
+		"    [ ] 3: Urgent fix [work]",
+		"",
+		"Medium",
+		"    [ ] 1: Old chore ·· [work]",
+		"",
+		"Low",
+		"    [X] 2: Old idea [work]",
+		"",
+	)
+}
+
+func TestSubtasks(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project home",
+		"add task home Move house",
+		"add task home Water the plants",
+		"add subtask 1 Pack the books",
+		"add subtask 1 Book a van",
+		"add subtask 4 Compare prices",
+	)
+	out.Reset()
+
+	executeAll(t, l, "check 1", "check 3", "check 5", "check 4", "check 1", "show")
+	assertOutput(t, out,
+		"Task 1 still has open subtasks, check them first.",
+		"home (4/5, 80%)",
+		"    [X] 1: Move house",
+		"      [X] 3: Pack the books",
+		"      [X] 4: Book a van",
+		"        [X] 5: Compare prices",
+		"    [ ] 2: Water the plants",
+		"",
+	)
+
+	executeAll(t, l, "uncheck 5", "delete 4", "show")
+	assertOutput(t, out,
+		"Deleted task 4: Book a van",
+		"home (1/4, 25%)",
+		"    [ ] 1: Move house",
+		"      [X] 3: Pack the books",
+		"    [ ] 2: Water the plants",
+		"    [ ] 5: Compare prices",
+		"",
+	)
+}
+
+func TestTaskDependencies(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Deploy",
+		"add task work Review PR",
+		"add task work Write tests",
+		"depends 1 on 2",
+		"task depends 1 on 3",
+		"depends 3 on 1",
+		"check 1",
+		"check 3",
+	)
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"Task 3 cannot depend on task 1, which waits for it.",
+		"Task 1 is blocked by 2, 3.",
+		"work (1/3, 33%)",
+		"    [ ] 1: Deploy blocked by 2",
+		"    [ ] 2: Review PR",
+		"    [X] 3: Write tests",
+		"",
+	)
+
+	executeAll(t, l, "delete 2", "check 1", "show")
+	assertOutput(t, out,
+		"Deleted task 2: Review PR",
+		"work (2/2, 100%)",
+		"    [X] 1: Deploy",
+		"    [X] 3: Write tests",
+		"",
+	)
+}
+
+func TestDailyCapacity(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"set capacity 6h",
+		"add project work",
+		"add task work Write report",
+		"add task work Review PR",
+		"add task work Plan sprint",
+		"size 1 L",
+		"size 2 S",
+		"priority 1 high",
+		"deadline 1 20240611",
+		"deadline 3 20240612",
+	)
+	out.Reset()
+
+	executeAll(t, l, "deadline 2 20240611")
+	assertOutput(t, out,
+		"Warning: 7h of work is due on 2024-06-11, more than the 6h daily capacity.",
+		"Nearest lighter day: 2024-06-12.",
+	)
+
+	executeAll(t, l, "agenda")
+	assertOutput(t, out,
+		"Next 3 days",
+		"    [ ] 1: (20240611) Write report [work]",
+		"    [ ] 2: (20240611) Review PR [work]",
+		"    [ ] 3: (20240612) Plan sprint [work]",
+		"",
+		"Warning: 7h of work is due on 2024-06-11, more than the 6h daily capacity.",
+	)
+
+	executeAll(t, l, "rebalance")
+	assertOutput(t, out, `Move task 2 "Review PR" from 2024-06-11 to 2024-06-12: deadline 2 20240612`)
+
+	executeAll(t, l, "deadline 2 20240612", "rebalance")
+	assertOutput(t, out, "No day is overloaded.")
+
+	executeAll(t, l, "estimate 1 4h30m", "estimate 3 2h", "deadline 3 20240611")
+	assertOutput(t, out,
+		"Warning: 6h30m of work is due on 2024-06-11, more than the 6h daily capacity.",
+		"Nearest lighter day: 2024-06-12.",
+	)
+}
+
+func TestTaskNotes(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l, "add project work", "add task work Review PR", "note 1 Asked Bob to have a look")
+	now = now.Add(150 * time.Minute)
+	executeAll(t, l, "task note 1 Bob approved, waiting for CI")
+	out.Reset()
+
+	executeAll(t, l, "show 1", "show 2")
+	assertOutput(t, out,
+		"work",
+		"    [ ] 1: Review PR",
+		"        created: 2024-06-10 09:00",
+		"        note 2024-06-10 09:00: Asked Bob to have a look",
+		"        note 2024-06-10 11:30: Bob approved, waiting for CI",
+		`Task with ID "2" not found.`,
+	)
+}
+
+func TestBulkCheckAndDelete(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Deploy",
+		"add task work Review PR",
+		"add task work Write tests",
+		"add task work Plan sprint",
+		"depends 1 on 3",
+	)
+	out.Reset()
+
+	executeAll(t, l, "check 1 2 7 4")
+	assertOutput(t, out,
+		"Task 1 is blocked by 3.",
+		"Checked task 2: Review PR",
+		`Task with ID "7" not found.`,
+		"Checked task 4: Plan sprint",
+	)
+
+	executeAll(t, l, "task uncheck 2 4", "delete 3 9 4", "show")
+	assertOutput(t, out,
+		"Unchecked task 2: Review PR",
+		"Unchecked task 4: Plan sprint",
+		"Deleted task 3: Write tests",
+		`Task with ID "9" not found.`,
+		"Deleted task 4: Plan sprint",
+		"work (0/2, 0%)",
+		"    [ ] 1: Deploy",
+		"    [ ] 2: Review PR",
+		"",
+	)
+
+	if err := l.execute("check"); err == nil {
+		t.Errorf("check without an ID should fail with its usage")
+	}
+}
+
+func TestImportCSVWithMapping(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	dir := t.TempDir()
+	mapping := filepath.Join(dir, "trello.yaml")
+	if err := os.WriteFile(mapping, []byte(strings.Join([]string{
+		"# Trello board export",
+		"description: Card Name",
+		"deadline: Due Date",
+		"date-format: DD/MM/YYYY",
+		"project: List",
+		"default-project: inbox",
+		"tags: Labels",
+		"tag-separator: ';'",
+		"done: Closed",
+	}, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cards := filepath.Join(dir, "cards.csv")
+	if err := os.WriteFile(cards, []byte(strings.Join([]string{
+		"Card Name,List,Due Date,Labels,Closed",
+		"Review PR,Work,11/06/2024,code; urgent,false",
+		"Buy milk,,,,",
+		"Plan sprint,work,31/02/2024,,",
+		",work,,,",
+		"Write tests,work,,,true",
+	}, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	executeAll(t, l, "add project work", "import csv "+mapping+" "+cards, "show")
+	assertOutput(t, out,
+		`Skipped row 4: invalid deadline "31/02/2024".`,
+		"Skipped row 5: no description.",
+		"Imported 3 tasks.",
+		"inbox (0/1, 0%)",
+		"    [ ] 2: Buy milk",
+		"",
+		"work (1/2, 50%)",
+		"    [ ] 1: (20240611) Review PR #code #urgent",
+		"    [X] 3: Write tests",
+		"",
+	)
+
+	executeAll(t, l, "import csv "+cards+" "+cards)
+	assertOutput(t, out, "Could not read mapping: mapping line 1: expected key: value.")
+
+	malformed := filepath.Join(dir, "malformed.csv")
+	if err := os.WriteFile(malformed, []byte(strings.Join([]string{
+		"Card Name,List,Due Date,Labels,Closed",
+		"Call the bank,home,,,",
+		"Pay rent,home,,,",
+		`Fix the "bike,home,,,`,
+	}, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	executeAll(t, l, "import csv "+mapping+" "+malformed, "show")
+	assertOutput(t, out,
+		`Could not read CSV: parse error on line 4, column 9: bare " in non-quoted-field.`,
+		"Imported 0 tasks.",
+		"inbox (0/1, 0%)",
+		"    [ ] 2: Buy milk",
+		"",
+		"work (1/2, 50%)",
+		"    [ ] 1: (20240611) Review PR #code #urgent",
+		"    [X] 3: Write tests",
+		"",
+	)
+}
+
+func TestUndo(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Deploy",
+		"add task work Review PR",
+		"add subtask 1 Write tests",
+		"depends 1 on 2",
+		"deadline 2 20240612",
+		"deadline 2 20240614",
+		"check 3 2",
+		"delete 2",
+	)
+	out.Reset()
+
+	executeAll(t, l, "undo", "undo", "show")
+	assertOutput(t, out,
+		`Undid "delete 2".`,
+		`Undid "check 3 2".`,
+		"work (0/3, 0%)",
+		"    [ ] 1: Deploy blocked by 2",
+		"      [ ] 3: Write tests",
+		"    [ ] 2: (20240614) Review PR",
+		"",
+	)
+
+	executeAll(t, l, "undo", "undo", "undo", "undo", "undo", "undo", "undo")
+	assertOutput(t, out,
+		`Undid "deadline 2 20240614".`,
+		`Undid "deadline 2 20240612".`,
+		`Undid "add subtask 1 Write tests".`,
+		`Undid "add task work Review PR".`,
+		`Undid "add task work Deploy".`,
+		`Undid "add project work".`,
+		"Nothing to undo.",
+	)
+}
+
+func TestRedo(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Deploy",
+		"add task work Review PR",
+		"deadline 1 20240612",
+		"check 2",
+		"delete 1",
+	)
+	out.Reset()
+
+	executeAll(t, l, "undo", "undo", "undo", "redo", "redo", "show")
+	assertOutput(t, out,
+		`Undid "delete 1".`,
+		`Undid "check 2".`,
+		`Undid "deadline 1 20240612".`,
+		`Redid "deadline 1 20240612".`,
+		`Redid "check 2".`,
+		"work (1/2, 50%)",
+		"    [ ] 1: (20240612) Deploy",
+		"    [X] 2: Review PR",
+		"",
+	)
+
+	executeAll(t, l, "add task work Write tests", "redo")
+	assertOutput(t, out, "Nothing to redo.")
+}
+
+func TestArchive(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Deploy",
+		"add task home Fix the bike",
+		"add task home Buy milk",
+		"check 1 3 4",
+	)
+	out.Reset()
+
+	executeAll(t, l, "archive 2", "archive 1", "show")
+	assertOutput(t, out,
+		"Task 2 is not done, check it before archiving it.",
+		"Archived task 1: Review PR",
+		"home (2/2, 100%)",
+		"    [X] 3: Fix the bike",
+		"    [X] 4: Buy milk",
+		"",
+		"work (0/1, 0%)",
+		"    [ ] 2: Deploy",
+		"",
+	)
+
+	executeAll(t, l, "archive done", "add task work Write tests", "show archived")
+	assertOutput(t, out,
+		"Archived 2 tasks.",
+		"home",
+		"    [X] 3: Fix the bike",
+		"    [X] 4: Buy milk",
+		"",
+		"work",
+		"    [X] 1: Review PR",
+		"",
+	)
+
+	executeAll(t, l, "show 5", "add task work --id 1 Write docs")
+	assertOutput(t, out,
+		"work",
+		"    [ ] 5: Write tests",
+		"        created: 2024-06-10 09:00",
+		`ID "1" is already used.`,
+	)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	session := filepath.Join(t.TempDir(), "session.log")
+	executeAll(t, l, "record "+session, "add project work", `add task work "Review PR"`)
+	now = now.Add(90 * time.Minute)
+	executeAll(t, l, "check 1", "record stop")
+	assertOutput(t, out,
+		"Recording commands to "+session+".",
+		"Stopped recording to "+session+".",
+	)
+
+	now = now.AddDate(0, 0, 3)
+	executeAll(t, l, "add task work Deploy", "replay "+session, "show --columns status,id,description,age")
+	assertOutput(t, out,
+		"Replayed 3 commands from "+session+".",
+		"work (1/1, 100%)",
+		"    [X] 1: Review PR",
+		"",
+	)
+	recording, err := os.ReadFile(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(recording)), "\n"); len(lines) != 3 || !strings.HasSuffix(lines[2], "\tcheck 1") {
+		t.Errorf("expected the three commands in the recording, got %q", recording)
+	}
+
+	executeAll(t, l, "replay "+filepath.Join(t.TempDir(), "missing.log"))
+	if !strings.HasPrefix(out.String(), "Could not replay: ") {
+		t.Errorf("expected the missing recording to be reported, got %q", out.String())
+	}
+}
+
+func TestClearCompleted(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Deploy",
+		"add task work Write tests",
+		"add task work Plan sprint",
+		"add task home Fix the bike",
+		"add task home Buy milk",
+		"check 1 2 3 5 6",
+	)
+	out.Reset()
+
+	executeAll(t, l, "clear completed work", "show")
+	assertOutput(t, out,
+		"Removed 3 completed tasks.",
+		"home (2/2, 100%)",
+		"    [X] 5: Fix the bike",
+		"    [X] 6: Buy milk",
+		"",
+		"work (0/1, 0%)",
+		"    [ ] 4: Plan sprint",
+		"",
+	)
+
+	executeAll(t, l, "undo", "clear completed --archive", "show archived")
+	assertOutput(t, out,
+		`Undid "clear completed work".`,
+		"Archived 5 completed tasks.",
+		"home",
+		"    [X] 5: Fix the bike",
+		"    [X] 6: Buy milk",
+		"",
+		"work",
+		"    [X] 1: Review PR",
+		"    [X] 2: Deploy",
+		"    [X] 3: Write tests",
+		"",
+	)
+
+	executeAll(t, l, "clear completed garden")
+	assertOutput(t, out, `Could not find a project with the name "garden".`)
+}
+
+func TestDueWithin(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Write report",
+		"add task home Fix the bike",
+		"add task work Review PR",
+		"add task work Pay invoice",
+		"deadline 1 20240617",
+		"deadline 2 20240612",
+		"deadline 3 20240610",
+		"deadline 4 20240607",
+	)
+	out.Reset()
+
+	executeAll(t, l, "due 7", "due 1")
+	assertOutput(t, out,
+		"Due within 7 days",
+		"    [ ] 3: (20240610) Review PR [work]",
+		"    [ ] 2: (20240612) Fix the bike [home]",
+		"    [ ] 1: (20240617) Write report [work]",
+		"",
+		"Due within 1 day",
+		"    [ ] 3: (20240610) Review PR [work]",
+		"",
+	)
+
+	executeAll(t, l, "check 3", "due 1")
+	assertOutput(t, out, "Nothing due within 1 day.")
+
+	if err := l.execute("due week"); err == nil {
+		t.Errorf("due without a number of days should fail with its usage")
+	}
+}
+
+func TestWeek(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Write report",
+		"add task home Fix the bike",
+		"add task work Review PR",
+		"add task work Plan sprint",
+		"deadline 1 20240612",
+		"deadline 2 20240612",
+		"deadline 3 20240610",
+		"deadline 4 20240617",
+	)
+	out.Reset()
+
+	executeAll(t, l, "week")
+	assertOutput(t, out,
+		"Mon 2024-06-10",
+		"    [ ] 3: (20240610) Review PR [work]",
+		"",
+		"Tue 2024-06-11",
+		"    Nothing due.",
+		"",
+		"Wed 2024-06-12",
+		"    [ ] 2: (20240612) Fix the bike [home]",
+		"    [ ] 1: (20240612) Write report [work]",
+		"",
+		"Thu 2024-06-13",
+		"    Nothing due.",
+		"",
+		"Fri 2024-06-14",
+		"    Nothing due.",
+		"",
+		"Sat 2024-06-15",
+		"    Nothing due.",
+		"",
+		"Sun 2024-06-16",
+		"    Nothing due.",
+		"",
+	)
+}
+
+func TestDeadlineTimeOfDay(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 12, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Send report",
+		"add task work Call Bob",
+		"add task work Review PR",
+		"deadline 1 2024-06-10T17:00",
+		"deadline 2 20240610T09:30",
+		"deadline 3 20240610",
+		"deadline 3 2024-06-10T25:00",
+	)
+	out.Reset()
+
+	executeAll(t, l, "today", "snooze 1 1d", "show")
+	assertOutput(t, out,
+		"work",
+		"    [ ] 1: (20240610 17:00) Send report due later today",
+		"    [ ] 2: (20240610 09:30) Call Bob already past",
+		"    [ ] 3: (20240610) Review PR",
+		"",
+		"Snoozed task 1 until 2024-06-11.",
+		"work (0/3, 0%)",
+		"    [ ] 1: (20240611 17:00) Send report",
+		"    [ ] 2: (20240610 09:30) Call Bob",
+		"    [ ] 3: (20240610) Review PR",
+		"",
+	)
+}
+
+func TestNaturalLanguageDeadlines(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 12, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Call Bob",
+		"add task work Send report",
+		"add task work Plan sprint",
+		"add task work Review PR",
+		"add task work Pay invoice",
+		"deadline 1 tomorrow",
+		"deadline 2 Friday",
+		"deadline 3 \"next week\"",
+		"deadline 4 wed",
+		"deadline 5 \"next month\"",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"work (0/5, 0%)",
+		"    [ ] 1: (20240613) Call Bob",
+		"    [ ] 2: (20240614) Send report",
+		"    [ ] 3: (20240617) Plan sprint",
+		"    [ ] 4: (20240619) Review PR",
+		"    [ ] 5: (20240701) Pay invoice",
+		"",
+	)
+
+	executeAll(t, l, `deadline 1 "some day"`, "deadline 2 fortnight")
+	assertOutput(t, out,
+		`Invalid date "some day".`,
+		`Invalid date "fortnight".`,
+	)
+}
+
+func TestReminders(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l,
+		"add project work",
+		"add task work Send report",
+		"add task work Review PR",
+		"add task work Plan sprint",
+		"deadline 1 2024-06-10T17:00",
+		"deadline 2 20240611",
+		"remind 1 2h",
+		"task remind 2 1d",
+		"remind 3 30m",
+	)
+	assertOutput(t, out, "Task 3 has no deadline yet, it will be reminded of once it has one.")
+
+	now = now.Add(5 * time.Hour)
+	l.giveReminders()
+	if out.Len() != 0 {
+		t.Errorf("expected no reminder before the lead time, got %q", out.String())
+	}
+
+	now = now.Add(time.Hour)
+	l.giveReminders()
+	l.giveReminders()
+	assertOutput(t, out, `Reminder: task 1 "Send report" is due at 2024-06-10 17:00.`)
+
+	now = now.Add(12 * time.Hour)
+	executeAll(t, l, "deadline 1 20240612")
+	l.giveReminders()
+	executeAll(t, l, "set display detailed", "show 1")
+	assertOutput(t, out,
+		`Reminder: task 2 "Review PR" is due on 2024-06-11.`,
+		"work",
+		"    [ ] 1: (20240612) Send report",
+		"        created: 2024-06-10 09:00",
+		"        reminder: 2h before",
+	)
+}
+
+func TestTimeTracking(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l, "add project work", "add task work Review PR", "add task work Deploy", "stop", "start 1")
+	now = now.Add(45 * time.Minute)
+	executeAll(t, l, "task start 2")
+	now = now.Add(20 * time.Minute)
+	executeAll(t, l, "start 1")
+	now = now.Add(50 * time.Minute)
+	executeAll(t, l, "show 1", "stop", "show 1")
+	assertOutput(t, out,
+		"No task is started.",
+		"Started task 1: Review PR",
+		"Stopped task 1 after 45m, 45m spent in total.",
+		"Started task 2: Deploy",
+		"Stopped task 2 after 20m, 20m spent in total.",
+		"Started task 1: Review PR",
+		"work",
+		"    [ ] 1: Review PR",
+		"        created: 2024-06-10 09:00",
+		"        time spent: 45m",
+		"        started: 2024-06-10 10:05",
+		"Stopped task 1 after 50m, 1h35m spent in total.",
+		"work",
+		"    [ ] 1: Review PR",
+		"        created: 2024-06-10 09:00",
+		"        time spent: 1h35m",
+	)
+}
+
+func TestPomodoro(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l, "add project work", "add task work Review PR", "pomodoro 1")
+	timer := l.focus
+	for _, minutes := range []int{6, 7, 12, 25, 26} {
+		now = time.Date(2024, time.June, 10, 9, minutes, 0, 0, time.Local)
+		l.tickPomodoro(timer)
+	}
+	executeAll(t, l, "pomodoro stop", "set display detailed", "show 1")
+	assertOutput(t, out,
+		"Started a 25m pomodoro on task 1: Review PR",
+		"Pomodoro on task 1: 20m left.",
+		"Pomodoro on task 1: 15m left.",
+		"Pomodoro on task 1 done, 1 pomodoro completed. Take a break!",
+		"No pomodoro is running.",
+		"work",
+		"    [ ] 1: Review PR",
+		"        created: 2024-06-10 09:00",
+		"        time spent: 25m",
+		"        pomodoros: 1",
+	)
+}
+
+func TestEstimates(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)
+	l, out := newTestTaskList(now)
+	l.now = func() time.Time { return now }
+	executeAll(t, l, "report estimates")
+	assertOutput(t, out, "No task has an estimate.")
+
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Deploy",
+		"add task home Fix the bike",
+		"estimate 1 1h",
+		"task estimate 2 2h",
+		"estimate 3 30m",
+		"start 1",
+	)
+	now = now.Add(90 * time.Minute)
+	executeAll(t, l, "start 2")
+	now = now.Add(165 * time.Minute)
+	executeAll(t, l, "start 3")
+	now = now.Add(15 * time.Minute)
+	executeAll(t, l, "stop")
+	out.Reset()
+
+	executeAll(t, l, "show --columns id,description,estimate", "report estimates")
+	assertOutput(t, out,
+		"home (0/1, 0%)",
+		"    3: Fix the bike ~30m",
+		"",
+		"work (0/2, 0%)",
+		"    1: Review PR ~1h",
+		"    2: Deploy    ~2h",
+		"",
+		"Estimated vs spent time",
+		"    home: estimated 30m, spent 15m (-50%) over 1 task",
+		"    work: estimated 3h, spent 4h15m (+41%) over 2 tasks",
+	)
+
+	if err := l.execute("estimate 1 soon"); err == nil {
+		t.Errorf("estimate with an invalid duration should fail with its usage")
+	}
+}
+
+func TestTaskProgress(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Write the thesis",
+		"add task work Migrate the database",
+		"progress 1 40",
+		"task progress 2 100",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"work (1/2, 50%)",
+		"    [40%] 1: Write the thesis",
+		"    [X] 2: Migrate the database",
+		"",
+	)
+
+	if err := l.execute("progress 1 120"); err == nil {
+		t.Errorf("progress above 100 should fail with its usage")
+	}
+}
+
+func TestAssignees(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add task work Write the thesis",
+		"add task work Migrate the database",
+		"add task work Plan the offsite",
+		"assign 1 alice",
+		"task assign 2 bob",
+		"assign 3 Alice",
+		"assign 3 none",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"work (0/3, 0%)",
+		"    [ ] 1: Write the thesis assignee:alice",
+		"    [ ] 2: Migrate the database assignee:bob",
+		"    [ ] 3: Plan the offsite",
+		"",
+	)
+
+	executeAll(t, l, "show assignee:ALICE")
+	assertOutput(t, out,
+		"work",
+		"    [ ] 1: Write the thesis assignee:alice",
+		"",
+	)
+
+	executeAll(t, l, "apply --dry-run assignee:bob check")
+	if !strings.Contains(out.String(), "2: Migrate the database") || strings.Contains(out.String(), "1: Write") {
+		t.Errorf("the assignee:bob query should match only task 2, got %q", out.String())
+	}
+
+	if err := l.execute("assign 1 alice@example.com"); err == nil {
+		t.Errorf("assigning an invalid name should fail with its usage")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// lighterDaySearchDays bounds how far from a busy day a lighter one is looked for.
+const lighterDaySearchDays = 30
+
+// estimatedHours is the work a task of each size is expected to take when it has no
+// estimate; tasks without a size are counted as small ones.
+var estimatedHours = map[size]int{
+	sizeNone:   1,
+	sizeSmall:  1,
+	sizeMedium: 3,
+	sizeLarge:  6,
+}
+
+// expectedWork returns how long a task is expected to take: its estimate, or else the
+// hours its size suggests.
+func expectedWork(task *Task) time.Duration {
+	if task.estimate > 0 {
+		return task.estimate
+	}
+	return time.Duration(estimatedHours[task.size]) * time.Hour
+}
+
+// dayLoad is the open work due on a day.
+type dayLoad struct {
+	tasks int
+	work  time.Duration
+}
+
+func (d dayLoad) plus(task *Task, sign int) dayLoad {
+	return dayLoad{tasks: d.tasks + sign, work: d.work + time.Duration(sign)*expectedWork(task)}
+}
+
+// loadOn returns the open tasks due on day and the work they are expected to take.
+func (l *TaskList) loadOn(day time.Time) dayLoad {
+	var load dayLoad
+	for _, tasks := range l.projectTasks {
+		for _, task := range tasks {
+			if !task.IsDone() && task.IsDueOn(day) {
+				load = load.plus(task, 1)
+			}
+		}
+	}
+	return load
+}
+
+// overloadWarnings describes how a day's load goes beyond the per day limit and the daily capacity.
+func (l *TaskList) overloadWarnings(day time.Time, load dayLoad) []string {
+	var warnings []string
+	if limit := l.settings.maxPerDay; limit > 0 && load.tasks > limit {
+		warnings = append(warnings, fmt.Sprintf("Warning: %d tasks are due on %s, more than the %d per day limit.",
+			load.tasks, day.Format("2006-01-02"), limit))
+	}
+	if capacity := l.settings.capacityHours; capacity > 0 && load.work > time.Duration(capacity)*time.Hour {
+		warnings = append(warnings, fmt.Sprintf("Warning: %s of work is due on %s, more than the %dh daily capacity.",
+			formatMinutes(load.work), day.Format("2006-01-02"), capacity))
+	}
+	return warnings
+}
+
+// hasRoom returns whether a day with the given load can take one more task.
+func (l *TaskList) hasRoom(load dayLoad, task *Task) bool {
+	if limit := l.settings.maxPerDay; limit > 0 && load.tasks >= limit {
+		return false
+	}
+	if capacity := l.settings.capacityHours; capacity > 0 && load.work+expectedWork(task) > time.Duration(capacity)*time.Hour {
+		return false
+	}
+	return true
+}
+
+// warnDeadlineLoad warns when the open tasks due on the deadline of task go beyond the
+// per day limit or the daily capacity, and suggests the nearest working day with room.
+func (l *TaskList) warnDeadlineLoad(task *Task) {
+	due, ok := task.deadline.Date()
+	if !ok || task.IsDone() {
+		return
+	}
+	warnings := l.overloadWarnings(due, l.loadOn(due))
+	if len(warnings) == 0 {
+		return
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(l.out, warning)
+	}
+	if lighter, ok := l.nearestLighterDay(due, task, l.loadOn); ok {
+		fmt.Fprintf(l.out, "Nearest lighter day: %s.\n", lighter.Format("2006-01-02"))
+	}
+}
+
+// nearestLighterDay looks around day, never before today, for the closest working day
+// with room for the task; later days win ties.
+func (l *TaskList) nearestLighterDay(day time.Time, task *Task, loadOn func(time.Time) dayLoad) (time.Time, bool) {
+	today := startOfDay(l.now())
+	for distance := 1; distance <= lighterDaySearchDays; distance++ {
+		for _, candidate := range []time.Time{day.AddDate(0, 0, distance), day.AddDate(0, 0, -distance)} {
+			if candidate.Before(today) || !l.settings.workdays.IsWorkingDay(candidate) {
+				continue
+			}
+			if l.hasRoom(loadOn(candidate), task) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// rebalance suggests moving the least urgent tasks of the overloaded days, from today on,
+// to the nearest days with room for them. Nothing is changed.
+func (l *TaskList) rebalance() {
+	today := startOfDay(l.now())
+	byDay := map[time.Time][]*Task{}
+	var days []time.Time
+	for _, ref := range l.openTasksByDeadline() {
+		due, _ := ref.task.deadline.Date()
+		if due.Before(today) {
+			continue
+		}
+		if _, ok := byDay[due]; !ok {
+			days = append(days, due)
+		}
+		byDay[due] = append(byDay[due], ref.task)
+	}
+
+	planned := map[time.Time]dayLoad{}
+	loadOn := func(day time.Time) dayLoad {
+		if load, ok := planned[day]; ok {
+			return load
+		}
+		return l.loadOn(day)
+	}
+	overloaded, moves := false, 0
+	for _, day := range days {
+		tasks := byDay[day]
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return l.effectivePriority(tasks[i]) < l.effectivePriority(tasks[j])
+		})
+		for _, task := range tasks {
+			load := loadOn(day)
+			if len(l.overloadWarnings(day, load)) == 0 {
+				break
+			}
+			overloaded = true
+			target, ok := l.nearestLighterDay(day, task, loadOn)
+			if !ok {
+				continue
+			}
+			planned[day] = load.plus(task, -1)
+			planned[target] = loadOn(target).plus(task, 1)
+			moves++
+			fmt.Fprintf(l.out, "Move task %s \"%s\" from %s to %s: deadline %s %s\n", task.GetID(), task.GetDescription(),
+				day.Format("2006-01-02"), target.Format("2006-01-02"), task.GetID(), target.Format(deadlineLayout))
+		}
+	}
+	switch {
+	case !overloaded:
+		fmt.Fprintln(l.out, "No day is overloaded.")
+	case moves == 0:
+		fmt.Fprintf(l.out, "No day with room found in the next %d days.\n", lighterDaySearchDays)
+	}
+}
@@ -4,25 +4,83 @@
 package main
 
 import (
+	"flag"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	jsonErrors := flag.Bool("json", false, "report command errors as JSON objects on stderr")
+	demo := flag.Bool("demo", false, "start with a sample list and a clock frozen at its date")
+	fixtures := flag.String("fixtures", "", "file of commands seeding the list in demo mode")
+	timings := flag.Bool("timings", false, "report how long each command took on stderr")
+	flag.Parse()
+
 	taskList := NewTaskList(os.Stdin, os.Stdout)
+	if *jsonErrors {
+		taskList.EnableJSONErrors(os.Stderr)
+	}
+	if *timings {
+		taskList.EnableTimings(os.Stderr)
+	}
+	if err := taskList.loadConfigFile(configPath()); err != nil {
+		println(err.Error())
+		os.Exit(1)
+	}
+	if *demo {
+		if err := taskList.SeedDemo(*fixtures); err != nil {
+			println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// a command given as arguments is run alone, leaving stdin and stdout to pipelines
+	if flag.NArg() > 0 {
+		if err := taskList.RunArgs(flag.Args()); err != nil {
+			println(err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// commands piped from a file or another program are run without prompts
+	if !isTerminal(os.Stdin) {
+		if err := taskList.RunBatch(os.Stderr); err != nil {
+			println(err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	shutdownChan := make(chan bool)
 	errorsChan := make(chan error)
+	terminateChan := make(chan os.Signal, 1)
+	signal.Notify(terminateChan, syscall.SIGTERM)
 
 	go func() {
 		taskList.Run(errorsChan, shutdownChan)
 	}()
+	go taskList.WatchReminders(reminderInterval)
 
-	select {
-	case err := <-errorsChan:
-		println(err)
-		os.Exit(1)
-	case <-shutdownChan:
-		println("finished")
-		os.Exit(0)
+	// failing commands are reported by Run and the session goes on, but they make it exit with an error
+	failed := false
+	for {
+		select {
+		case <-errorsChan:
+			failed = true
+		case <-shutdownChan:
+			println("finished")
+			if failed {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		case <-terminateChan:
+			// the lock is kept until exiting, so no command or reminder changes the list meanwhile
+			taskList.mu.Lock()
+			taskList.EndSession()
+			os.Exit(0)
+		}
 	}
 
 }
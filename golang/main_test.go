@@ -143,7 +143,7 @@ func TestRunWithDeadline(t *testing.T) {
 	fmt.Println("(show tasks)")
 	tester.execute("show")
 	tester.readLines([]string{
-		"secrets",
+		"secrets (0/2, 0%)",
 		"    [ ] 1: (1595352997) Eat more donuts.",
 		"    [ ] 2: (1595352922) Destroy all humans.",
 		"",
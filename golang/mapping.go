@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importMapping declares how the columns of a CSV file map to task fields.
+// Field entries name a column of the header row; the others tune the conversion.
+type importMapping struct {
+	description string
+	deadline    string
+	project     string
+	tags        string
+	done        string
+	// dateFormat is the Go layout of the deadline column, e.g. "02/01/2006".
+	dateFormat     string
+	tagSeparator   string
+	defaultProject string
+}
+
+// dateFormatTokens translate the date format of a mapping, e.g. "DD/MM/YYYY", into a Go layout.
+var dateFormatTokens = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02")
+
+// parseImportMapping reads a mapping written as "key: value" lines, the flat subset
+// of YAML, e.g. "description: Title" or "date-format: DD/MM/YYYY".
+// Values may be quoted, and lines starting with # are comments.
+func parseImportMapping(in io.Reader) (importMapping, error) {
+	mapping := importMapping{dateFormat: deadlineLayout, tagSeparator: ","}
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return importMapping{}, fmt.Errorf("mapping line %d: expected key: value", lineNumber)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+			unquoted, err := unquoteMappingValue(value)
+			if err != nil {
+				return importMapping{}, fmt.Errorf("mapping line %d: invalid quoted value %s", lineNumber, value)
+			}
+			value = unquoted
+		}
+		switch key {
+		case "description":
+			mapping.description = value
+		case "deadline":
+			mapping.deadline = value
+		case "project":
+			mapping.project = value
+		case "tags":
+			mapping.tags = value
+		case "done":
+			mapping.done = value
+		case "date-format":
+			mapping.dateFormat = dateFormatTokens.Replace(value)
+		case "tag-separator":
+			mapping.tagSeparator = value
+		case "default-project":
+			mapping.defaultProject = value
+		default:
+			return importMapping{}, fmt.Errorf("mapping line %d: unknown key %q", lineNumber, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return importMapping{}, err
+	}
+	if mapping.description == "" {
+		return importMapping{}, fmt.Errorf("mapping has no description column")
+	}
+	if mapping.project == "" && mapping.defaultProject == "" {
+		return importMapping{}, fmt.Errorf("mapping has neither a project column nor a default-project")
+	}
+	if mapping.tagSeparator == "" {
+		return importMapping{}, fmt.Errorf("mapping has an empty tag-separator")
+	}
+	return mapping, nil
+}
+
+func unquoteMappingValue(value string) (string, error) {
+	if strings.HasPrefix(value, "'") {
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	}
+	return strconv.Unquote(value)
+}
+
+// importCSV creates tasks from the rows of a CSV file, or of the standard input when
+// the path is "-", as declared by the mapping file. Missing projects are created,
+// and rows that cannot be converted are reported and skipped. When the file cannot be
+// read to its end, no task is imported.
+func (l *TaskList) importCSV(mappingPath, path string) {
+	mappingFile, err := l.openInput(mappingPath)
+	if err != nil {
+		l.fail(codeImportFailed, "Could not read mapping: %v.", err)
+		return
+	}
+	mapping, err := parseImportMapping(mappingFile)
+	mappingFile.Close()
+	if err != nil {
+		l.fail(codeImportFailed, "Could not read mapping: %v.", err)
+		return
+	}
+
+	file, err := l.openInput(path)
+	if err != nil {
+		l.fail(codeImportFailed, "Could not read CSV: %v.", err)
+		return
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		l.fail(codeImportFailed, "Could not read CSV header: %v.", err)
+		return
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{mapping.description, mapping.deadline, mapping.project, mapping.tags, mapping.done} {
+		if _, ok := columns[name]; name != "" && !ok {
+			l.fail(codeImportFailed, "Could not find the column \"%s\" in the CSV header.", name)
+			return
+		}
+	}
+
+	// a file that cannot be read to its end imports nothing, not the rows before the error
+	imported := 0
+	if !l.atomically(func() bool {
+		for row := 2; ; row++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return true
+			}
+			if err != nil {
+				l.fail(codeImportFailed, "Could not read CSV: %v.", err)
+				return false
+			}
+			field := func(name string) string {
+				if i, ok := columns[name]; ok && name != "" && i < len(record) {
+					return strings.TrimSpace(record[i])
+				}
+				return ""
+			}
+			if err := l.importRow(mapping, field); err != nil {
+				l.fail(codeImportFailed, "Skipped row %d: %v.", row, err)
+				continue
+			}
+			imported++
+		}
+	}) {
+		imported = 0
+	}
+	fmt.Fprintf(l.out, "Imported %s.\n", countOf(imported, "task"))
+}
+
+// importRow creates the task described by the fields of a row, once all of them converted.
+func (l *TaskList) importRow(mapping importMapping, field func(name string) string) error {
+	description := l.settings.descriptions.Apply(field(mapping.description))
+	if description == "" {
+		return fmt.Errorf("no description")
+	}
+	projectName := field(mapping.project)
+	if projectName == "" {
+		projectName = mapping.defaultProject
+	}
+	if projectName == "" {
+		return fmt.Errorf("no project")
+	}
+
+	var due deadline
+	if value := field(mapping.deadline); value != "" {
+		date, err := time.ParseInLocation(mapping.dateFormat, value, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid deadline %q", value)
+		}
+		due, _ = NewDeadline(date.Format(deadlineLayout))
+	}
+	var labels []string
+	for _, label := range strings.Split(field(mapping.tags), mapping.tagSeparator) {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	tags, ok := parseTags(labels)
+	if !ok {
+		return fmt.Errorf("invalid tags %q", field(mapping.tags))
+	}
+
+	if project, ok := l.findProject(projectName); ok {
+		projectName = project
+	} else {
+		l.projectTasks[projectName] = make([]*Task, 0)
+		l.recordUndo(
+			func() { delete(l.projectTasks, projectName) },
+			func() { l.projectTasks[projectName] = make([]*Task, 0) },
+		)
+	}
+	task := l.addTask(projectName, description)
+	task.SetDeadline(due)
+	task.SetTags(tags, true)
+	if isTruthy(field(mapping.done)) {
+		task.SetDone(true, l.now())
+	}
+	return nil
+}
+
+// isTruthy tells whether a CSV cell marks a task as done.
+func isTruthy(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "x", "y", "yes", "true", "done":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// normalizeStep is a named transformation applied to new descriptions.
+type normalizeStep struct {
+	name  string
+	apply func(description string) string
+}
+
+var normalizeSteps = []normalizeStep{
+	{name: "trim", apply: strings.TrimSpace},
+	{name: "collapse", apply: func(description string) string {
+		return strings.Join(strings.Fields(description), " ")
+	}},
+	{name: "capitalize", apply: func(description string) string {
+		first, size := utf8.DecodeRuneInString(description)
+		if first == utf8.RuneError {
+			return description
+		}
+		return string(unicode.ToUpper(first)) + description[size:]
+	}},
+}
+
+// replacement is a user rule replacing a whole word or phrase in descriptions.
+type replacement struct {
+	from    string
+	to      string
+	pattern *regexp.Regexp
+}
+
+// descriptionPipeline tidies the descriptions of new tasks: the enabled steps run in the
+// order trim, collapse, then the replacement rules, then capitalize.
+type descriptionPipeline struct {
+	steps        map[string]bool
+	replacements []replacement
+}
+
+func (p descriptionPipeline) Apply(description string) string {
+	for _, step := range normalizeSteps {
+		if step.name == "capitalize" {
+			for _, rule := range p.replacements {
+				description = rule.pattern.ReplaceAllLiteralString(description, rule.to)
+			}
+		}
+		if p.steps[step.name] {
+			description = step.apply(description)
+		}
+	}
+	return description
+}
+
+func (p descriptionPipeline) StepsString() string {
+	var names []string
+	for _, step := range normalizeSteps {
+		if p.steps[step.name] {
+			names = append(names, step.name)
+		}
+	}
+	if len(names) == 0 {
+		return "off"
+	}
+	return strings.Join(names, ",")
+}
+
+// SetSteps enables the comma-separated steps, "off" disabling them all.
+func (p *descriptionPipeline) SetSteps(value string) error {
+	steps := map[string]bool{}
+	if value != "off" {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if !isNormalizeStep(name) {
+				return fmt.Errorf("invalid normalize step %q, expected trim, collapse, capitalize or off", name)
+			}
+			steps[name] = true
+		}
+	}
+	p.steps = steps
+	return nil
+}
+
+func isNormalizeStep(name string) bool {
+	for _, step := range normalizeSteps {
+		if step.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p descriptionPipeline) ReplacementsString() string {
+	if len(p.replacements) == 0 {
+		return "none"
+	}
+	rules := make([]string, len(p.replacements))
+	for i, rule := range p.replacements {
+		rules[i] = rule.from + " => " + rule.to
+	}
+	return strings.Join(rules, "; ")
+}
+
+// AddReplacement appends a "from => to" rule, "none" removing all the rules.
+func (p *descriptionPipeline) AddReplacement(value string) error {
+	if value == "none" {
+		p.replacements = nil
+		return nil
+	}
+	parts := strings.SplitN(value, "=>", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("invalid replacement %q, expected <from> => <to>", value)
+	}
+	from, to := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	expr := regexp.QuoteMeta(from)
+	if first, _ := utf8.DecodeRuneInString(from); isWordRune(first) {
+		expr = `\b` + expr
+	}
+	if last, _ := utf8.DecodeLastRuneInString(from); isWordRune(last) {
+		expr += `\b`
+	}
+	p.replacements = append(p.replacements, replacement{from: from, to: to, pattern: regexp.MustCompile("(?i)" + expr)})
+	return nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// taskNote is a remark added to a task to track its progress.
+type taskNote struct {
+	at   time.Time
+	text string
+}
+
+func (l *TaskList) note(idString, text string) {
+	task, err := l.getTaskBy(idString)
+	if err != nil {
+		return
+	}
+	task.notes = append(task.notes, taskNote{at: l.now(), text: text})
+}
+
+// noteDetails returns the notes of a task, oldest first, as detail lines.
+func noteDetails(task *Task) []string {
+	details := make([]string, 0, len(task.notes))
+	for _, n := range task.notes {
+		details = append(details, fmt.Sprintf("note %s: %s", n.at.Format(timestampLayout), n.text))
+	}
+	return details
+}
+
+// showTask prints a single task with all its details and notes, under its project.
+func (l *TaskList) showTask(task *Task) {
+	fmt.Fprintln(l.out, l.projectOf(task))
+	details := func(t *Task) []string {
+		if l.tracked != t {
+			return taskDetails(t)
+		}
+		return append(taskDetails(t), "started: "+l.trackedSince.Format(timestampLayout))
+	}
+	table := &taskTable{columns: displayColumns, options: l.renderOptions(), details: details}
+	table.Add(task)
+	table.Flush(l.out)
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const notifyUsage = "could not execute notify. Usage: notify test [<channel>]"
+
+// Events a notification can be sent for, routed to channels by the notify setting.
+const (
+	eventDone     = "done"
+	eventDeleted  = "deleted"
+	eventReminder = "reminder"
+	eventTest     = "test"
+)
+
+var notifyEvents = []string{eventDone, eventDeleted, eventReminder}
+
+// Notifier delivers the rendering of an event through a channel.
+type Notifier interface {
+	Send(event, rendering string) error
+}
+
+// notifierRegistrations build the notifier of each channel from the settings,
+// so a new channel only needs an entry here.
+var notifierRegistrations = map[string]func(s *settings) (Notifier, error){
+	"desktop": func(*settings) (Notifier, error) { return desktopNotifier{}, nil },
+	"webhook": func(s *settings) (Notifier, error) {
+		if s.webhookURL == "" {
+			return nil, fmt.Errorf("the webhook-url setting is empty")
+		}
+		return webhookNotifier{url: s.webhookURL}, nil
+	},
+	"slack": func(s *settings) (Notifier, error) {
+		if s.slackURL == "" {
+			return nil, fmt.Errorf("the slack-url setting is empty")
+		}
+		return slackNotifier{url: s.slackURL}, nil
+	},
+	"email": func(s *settings) (Notifier, error) {
+		if s.smtpServer == "" || s.emailTo == "" {
+			return nil, fmt.Errorf("the smtp-server and email-to settings are needed")
+		}
+		return emailNotifier{server: s.smtpServer, to: s.emailTo}, nil
+	},
+	"telegram": func(s *settings) (Notifier, error) {
+		if s.telegramToken == "" || s.telegramChat == "" {
+			return nil, fmt.Errorf("the telegram-token and telegram-chat settings are needed")
+		}
+		return telegramNotifier{token: s.telegramToken, chat: s.telegramChat}, nil
+	},
+}
+
+// parseNotifyRoutes reads routing rules such as "done=desktop,slack deleted=webhook",
+// or "off" to send no notification.
+func parseNotifyRoutes(value string) (map[string][]string, error) {
+	routes := map[string][]string{}
+	if value == "off" {
+		return routes, nil
+	}
+	for _, rule := range strings.Fields(value) {
+		parts := strings.SplitN(rule, "=", 2)
+		if len(parts) != 2 || !isNotifyEvent(parts[0]) {
+			return nil, fmt.Errorf("invalid rule %q, expected <event>=<channel>,... with events %s", rule, strings.Join(notifyEvents, ", "))
+		}
+		for _, channel := range strings.Split(parts[1], ",") {
+			if _, ok := notifierRegistrations[channel]; !ok {
+				return nil, fmt.Errorf("unknown channel %q, expected %s", channel, strings.Join(notifierChannels(), ", "))
+			}
+			routes[parts[0]] = append(routes[parts[0]], channel)
+		}
+	}
+	return routes, nil
+}
+
+func formatNotifyRoutes(routes map[string][]string) string {
+	var rules []string
+	for _, event := range notifyEvents {
+		if channels := routes[event]; len(channels) > 0 {
+			rules = append(rules, event+"="+strings.Join(channels, ","))
+		}
+	}
+	if len(rules) == 0 {
+		return "off"
+	}
+	return strings.Join(rules, " ")
+}
+
+func isNotifyEvent(name string) bool {
+	for _, event := range notifyEvents {
+		if event == name {
+			return true
+		}
+	}
+	return false
+}
+
+func notifierChannels() []string {
+	channels := make([]string, 0, len(notifierRegistrations))
+	for channel := range notifierRegistrations {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// notify sends the rendering of an event through the channels it is routed to.
+// A channel failing is reported without failing the command that raised the event.
+func (l *TaskList) notify(event, rendering string) {
+	for _, channel := range l.settings.notifyRoutes[event] {
+		l.sendNotification(channel, event, rendering)
+	}
+}
+
+func (l *TaskList) sendNotification(channel, event, rendering string) bool {
+	notifier, err := notifierRegistrations[channel](&l.settings)
+	if err == nil {
+		err = notifier.Send(event, rendering)
+	}
+	if err != nil {
+		l.fail(codeNotifyFailed, "Could not notify through %s: %v.", channel, err)
+		return false
+	}
+	return true
+}
+
+// notifyTest sends a test notification through a channel, or through every routed channel.
+func (l *TaskList) notifyTest(args []string) error {
+	if len(args) < 1 || args[0] != "test" || len(args) > 2 {
+		return fmt.Errorf(notifyUsage)
+	}
+	var channels []string
+	if len(args) == 2 {
+		if _, ok := notifierRegistrations[args[1]]; !ok {
+			l.fail(codeNotifyFailed, "Unknown channel \"%s\", expected %s.", args[1], strings.Join(notifierChannels(), ", "))
+			return nil
+		}
+		channels = []string{args[1]}
+	} else {
+		routed := map[string]bool{}
+		for _, event := range notifyEvents {
+			for _, channel := range l.settings.notifyRoutes[event] {
+				if !routed[channel] {
+					routed[channel] = true
+					channels = append(channels, channel)
+				}
+			}
+		}
+	}
+	if len(channels) == 0 {
+		fmt.Fprintln(l.out, "No channel to notify, route events with the notify setting.")
+		return nil
+	}
+	for _, channel := range channels {
+		if l.sendNotification(channel, eventTest, "This is a test notification from task-list.") {
+			fmt.Fprintf(l.out, "Sent a test notification through %s.\n", channel)
+		}
+	}
+	return nil
+}
+
+// desktopNotifier shows a notification on the desktop of the user.
+type desktopNotifier struct{}
+
+func (desktopNotifier) Send(event, rendering string) error {
+	title := "task-list: " + event
+	if runtime.GOOS == "darwin" {
+		script := fmt.Sprintf("display notification %q with title %q", rendering, title)
+		return exec.Command("osascript", "-e", script).Run()
+	}
+	return exec.Command("notify-send", title, rendering).Run()
+}
+
+// notifyTimeout bounds the time a command waits for a remote channel.
+const notifyTimeout = 5 * time.Second
+
+// webhookNotifier posts events as JSON to a URL.
+type webhookNotifier struct {
+	url string
+}
+
+func (n webhookNotifier) Send(event, rendering string) error {
+	return postJSON(n.url, map[string]string{"event": event, "text": rendering})
+}
+
+// slackNotifier posts events to a Slack incoming webhook.
+type slackNotifier struct {
+	url string
+}
+
+func (n slackNotifier) Send(_, rendering string) error {
+	return postJSON(n.url, map[string]string{"text": rendering})
+}
+
+// emailNotifier mails events to an address, from that same address, through an SMTP
+// server accepting mail without authentication, such as a local relay.
+type emailNotifier struct {
+	server, to string
+}
+
+func (n emailNotifier) Send(event, rendering string) error {
+	conn, err := net.DialTimeout("tcp", n.server, notifyTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(notifyTimeout))
+	host, _, _ := net.SplitHostPort(n.server)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if err := client.Mail(n.to); err != nil {
+		return err
+	}
+	if err := client.Rcpt(n.to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: task-list: %s\r\n\r\n%s\r\n", n.to, n.to, event, rendering)
+	if _, err := w.Write([]byte(message)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// telegramAPI is the address of the Telegram Bot API, replaced in tests.
+var telegramAPI = "https://api.telegram.org"
+
+// telegramNotifier sends events to a chat through a Telegram bot.
+type telegramNotifier struct {
+	token, chat string
+}
+
+func (n telegramNotifier) Send(_, rendering string) error {
+	return postJSON(telegramAPI+"/bot"+n.token+"/sendMessage", map[string]string{"chat_id": n.chat, "text": rendering})
+}
+
+func postJSON(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: notifyTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifyWebhook(t *testing.T) {
+	var received []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		received = append(received, payload)
+	}))
+	defer server.Close()
+
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"set webhook-url "+server.URL,
+		"set notify done=webhook",
+		"add project work",
+		"add task work Review PR",
+		"add task work Deploy",
+		"check 1",
+		"delete 2",
+		"notify test",
+	)
+	assertOutput(t, out,
+		"Deleted task 2: Deploy",
+		"Sent a test notification through webhook.",
+	)
+	expected := []map[string]string{
+		{"event": "done", "text": "Task 1 done: Review PR"},
+		{"event": "test", "text": "This is a test notification from task-list."},
+	}
+	if len(received) != len(expected) {
+		t.Fatalf("expected %d notifications, got %v", len(expected), received)
+	}
+	for i := range expected {
+		if received[i]["event"] != expected[i]["event"] || received[i]["text"] != expected[i]["text"] {
+			t.Errorf("notification %d: expected %v, got %v", i, expected[i], received[i])
+		}
+	}
+
+	executeAll(t, l, "notify test slack", "set notify done=pager")
+	assertOutput(t, out,
+		"Could not notify through slack: the slack-url setting is empty.",
+		`Could not change setting: unknown channel "pager", expected desktop, email, slack, telegram, webhook.`,
+	)
+}
+
+func TestNotifyTelegram(t *testing.T) {
+	var path string
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+	}))
+	defer server.Close()
+	defer func(api string) { telegramAPI = api }(telegramAPI)
+	telegramAPI = server.URL
+
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "notify test telegram", "set telegram-token 123:abc", "set telegram-chat 42", "notify test telegram")
+	assertOutput(t, out,
+		"Could not notify through telegram: the telegram-token and telegram-chat settings are needed.",
+		"Sent a test notification through telegram.",
+	)
+	if path != "/bot123:abc/sendMessage" || received["chat_id"] != "42" || received["text"] != "This is a test notification from task-list." {
+		t.Errorf("unexpected message to %s: %v", path, received)
+	}
+}
+
+func TestNotifyEmail(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	mail := make(chan string, 1)
+	go serveOneMail(listener, mail)
+
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"set smtp-server "+listener.Addr().String(),
+		"set email-to me@example.com",
+		"notify test email",
+	)
+	assertOutput(t, out, "Sent a test notification through email.")
+	message := <-mail
+	for _, want := range []string{"MAIL FROM:<me@example.com>", "RCPT TO:<me@example.com>", "Subject: task-list: test", "This is a test notification from task-list."} {
+		if !strings.Contains(message, want) {
+			t.Errorf("expected %q in the mail session, got:\n%s", want, message)
+		}
+	}
+}
+
+// serveOneMail answers a single SMTP session, sending what the client wrote on mail.
+func serveOneMail(listener net.Listener, mail chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	var session strings.Builder
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 localhost ready\r\n")
+	inData := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		session.WriteString(line)
+		switch {
+		case inData:
+			if line == ".\r\n" {
+				inData = false
+				fmt.Fprint(conn, "250 queued\r\n")
+			}
+		case strings.HasPrefix(line, "DATA"):
+			inData = true
+			fmt.Fprint(conn, "354 go ahead\r\n")
+		case strings.HasPrefix(line, "QUIT"):
+			fmt.Fprint(conn, "221 bye\r\n")
+			mail <- session.String()
+			return
+		default:
+			fmt.Fprint(conn, "250 ok\r\n")
+		}
+	}
+	mail <- session.String()
+}
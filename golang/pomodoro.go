@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	pomodoroUsage = "could not execute pomodoro. Usage: pomodoro <task ID> | pomodoro stop"
+	// pomodoroLength is the focus time of a pomodoro.
+	pomodoroLength = 25 * time.Minute
+	// pomodoroTick is how often the timer checks the time, and pomodoroProgress how
+	// often it reports the time left.
+	pomodoroTick     = time.Minute
+	pomodoroProgress = 5 * time.Minute
+)
+
+// focusTimer is a running pomodoro on a task.
+type focusTimer struct {
+	task    *Task
+	started time.Time
+	// reported is the time elapsed at the last progress report.
+	reported time.Duration
+}
+
+// pomodoro starts a focus timer on a task, running in the background so commands can
+// still be typed, or stops the running one.
+func (l *TaskList) pomodoro(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf(pomodoroUsage)
+	}
+	if args[0] == "stop" {
+		if l.focus == nil {
+			l.fail(codeNotTracking, "No pomodoro is running.")
+			return nil
+		}
+		fmt.Fprintf(l.out, "Stopped the pomodoro on task %s.\n", l.focus.task.GetID())
+		l.focus = nil
+		return nil
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	if l.focus != nil {
+		fmt.Fprintf(l.out, "Stopped the pomodoro on task %s.\n", l.focus.task.GetID())
+	}
+	timer := &focusTimer{task: task, started: l.now()}
+	l.focus = timer
+	fmt.Fprintf(l.out, "Started a %s pomodoro on task %s: %s\n", formatMinutes(pomodoroLength), task.GetID(), task.GetDescription())
+	go l.runPomodoro(timer)
+	return nil
+}
+
+func (l *TaskList) runPomodoro(timer *focusTimer) {
+	ticker := time.NewTicker(pomodoroTick)
+	defer ticker.Stop()
+	for range ticker.C {
+		running := true
+		l.inBackground(func() { running = l.tickPomodoro(timer) })
+		if !running {
+			return
+		}
+	}
+}
+
+// tickPomodoro reports the progress of a focus timer every few minutes, and logs the
+// pomodoro on its task once over. It returns whether the timer is still running.
+func (l *TaskList) tickPomodoro(timer *focusTimer) bool {
+	if l.focus != timer {
+		return false
+	}
+	elapsed := l.now().Sub(timer.started)
+	if elapsed >= pomodoroLength {
+		l.focus = nil
+		timer.task.pomodoros++
+		timer.task.spent += pomodoroLength
+		fmt.Fprintf(l.out, "Pomodoro on task %s done, %s completed. Take a break!\n",
+			timer.task.GetID(), countOf(timer.task.pomodoros, "pomodoro"))
+		return false
+	}
+	if elapsed-timer.reported >= pomodoroProgress {
+		timer.reported = elapsed.Truncate(pomodoroProgress)
+		fmt.Fprintf(l.out, "Pomodoro on task %s: %s left.\n", timer.task.GetID(), formatMinutes(pomodoroLength-timer.reported))
+	}
+	return true
+}
+
+// pomodoroDetail tells how many pomodoros were completed on a task, for the detailed display.
+func pomodoroDetail(task *Task) (string, bool) {
+	if task.pomodoros == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("pomodoros: %d", task.pomodoros), true
+}
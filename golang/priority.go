@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+const priorityUsage = "could not execute priority. Usage: priority <task ID> high|medium|low|none"
+
+// priority tells which tasks to do first; tasks without a priority come last.
+type priority int
+
+const (
+	priorityNone priority = iota
+	priorityLow
+	priorityMedium
+	priorityHigh
+)
+
+var priorityNames = map[priority]string{
+	priorityNone:   "none",
+	priorityLow:    "low",
+	priorityMedium: "medium",
+	priorityHigh:   "high",
+}
+
+func (p priority) String() string {
+	return priorityNames[p]
+}
+
+func parsePriority(value string) (priority, bool) {
+	for p, name := range priorityNames {
+		if name == value {
+			return p, true
+		}
+	}
+	return priorityNone, false
+}
+
+func (l *TaskList) setPriority(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf(priorityUsage)
+	}
+	p, ok := parsePriority(args[1])
+	if !ok {
+		return fmt.Errorf(priorityUsage)
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.priority = p
+	return nil
+}
+
+// effectivePriority returns the priority of a task, raised one level for each priority
+// aging threshold its age reached while open, up to high.
+func (l *TaskList) effectivePriority(task *Task) priority {
+	p := task.priority
+	if task.IsDone() || task.GetCreated().IsZero() {
+		return p
+	}
+	age := ageInDays(task, startOfDay(l.now()), l.settings.workdays)
+	for _, threshold := range l.settings.priorityAging {
+		if age >= threshold && p < priorityHigh {
+			p++
+		}
+	}
+	return p
+}
+
+// byPriority returns the tasks with the highest effective priorities first, keeping the
+// order of tasks with the same priority.
+func (l *TaskList) byPriority(tasks []*Task) []*Task {
+	sorted := append([]*Task(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return l.effectivePriority(sorted[i]) > l.effectivePriority(sorted[j])
+	})
+	return sorted
+}
+
+// viewByPriority prints every task grouped by effective priority, highest first.
+func (l *TaskList) viewByPriority() {
+	groups := map[priority][]taskRef{}
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			p := l.effectivePriority(task)
+			groups[p] = append(groups[p], taskRef{project: project, task: task})
+		}
+	}
+	if len(groups) == 0 {
+		fmt.Fprintln(l.out, "No tasks.")
+		return
+	}
+	for _, p := range []priority{priorityHigh, priorityMedium, priorityLow} {
+		l.printAgendaSection(capitalize(p.String()), groups[p])
+	}
+	l.printAgendaSection("No priority", groups[priorityNone])
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const progressBarWidth = 10
+
+// progress counts the completed tasks among a set of tasks.
+type progress struct {
+	done  int
+	total int
+}
+
+func progressOf(tasks []*Task) progress {
+	p := progress{total: len(tasks)}
+	for _, task := range tasks {
+		if task.IsDone() {
+			p.done++
+		}
+	}
+	return p
+}
+
+// Percent returns the rounded-down share of completed tasks, 0 when there are no tasks.
+func (p progress) Percent() int {
+	if p.total == 0 {
+		return 0
+	}
+	return p.done * 100 / p.total
+}
+
+func (p progress) String() string {
+	return fmt.Sprintf("%d/%d, %d%%", p.done, p.total, p.Percent())
+}
+
+// Bar renders the completion as an ASCII progress bar.
+func (p progress) Bar() string {
+	filled := p.Percent() * progressBarWidth / 100
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled) + "]"
+}
+
+// projectHeader returns the project name followed by its completion, and the progress bar when enabled.
+func (l *TaskList) projectHeader(project string) string {
+	p := progressOf(l.projectTasks[project])
+	header := fmt.Sprintf("%s (%s)", project, p)
+	if l.settings.progressBars {
+		header += " " + p.Bar()
+	}
+	return header
+}
+
+// summary counts the tasks of every project by status.
+type summary struct {
+	open     int
+	done     int
+	overdue  int
+	dueToday int
+}
+
+func (s summary) String() string {
+	return fmt.Sprintf("Open: %d, done: %d, overdue: %d, due today: %d", s.open, s.done, s.overdue, s.dueToday)
+}
+
+// summary totals the tasks of all projects, counting overdue and due today among the open ones.
+func (l *TaskList) summary() summary {
+	today := startOfDay(l.now())
+	var s summary
+	for _, tasks := range l.projectTasks {
+		for _, task := range tasks {
+			if task.IsDone() {
+				s.done++
+				continue
+			}
+			s.open++
+			if due, ok := task.deadline.Date(); ok {
+				if due.Before(today) {
+					s.overdue++
+				} else if task.IsDueOn(today) {
+					s.dueToday++
+				}
+			}
+		}
+	}
+	return s
+}
+
+const taskProgressUsage = "could not execute progress. Usage: progress <task ID> <0-100>"
+
+// setTaskProgress records how far a long-running task has gone; reaching 100 checks it.
+func (l *TaskList) setTaskProgress(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf(taskProgressUsage)
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(args[1], "%"))
+	if err != nil || percent < 0 || percent > 100 {
+		return fmt.Errorf(taskProgressUsage)
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	if percent == 100 {
+		l.complete(task, true)
+		return nil
+	}
+	task.percent = percent
+	return nil
+}
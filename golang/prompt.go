@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// promptData is the context available to the prompt template.
+type promptData struct {
+	Workspace string
+	Project   string
+	OpenCount int
+}
+
+// prompt renders the prompt template, falling back to the default prompt when it fails.
+func (l *TaskList) prompt() string {
+	data := promptData{
+		Workspace: l.settings.workspace,
+		Project:   l.currentProject,
+		OpenCount: l.summary().open,
+	}
+	var rendered strings.Builder
+	if err := l.settings.prompt.Execute(&rendered, data); err != nil {
+		return prompt
+	}
+	return rendered.String()
+}
+
+// printPrompt writes the prompt while holding the lock, as background work such as
+// reminders swaps l.out and changes the list while it runs.
+func (l *TaskList) printPrompt() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprint(l.out, l.prompt())
+}
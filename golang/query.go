@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryTerm is a single condition of a query, possibly negated with a leading "-".
+type queryTerm struct {
+	negated bool
+	match   func(ref taskRef) bool
+}
+
+// query selects tasks matching all its terms. Terms are separated by spaces:
+//
+//	all                 every task
+//	done, pending       checked or unchecked tasks
+//	overdue, today      open tasks due before or on today
+//	project:<name>      tasks of a project
+//	size:<S|M|L>        tasks of a size
+//	goal:<goal ID>      tasks linked to a goal
+//	tag:<label>         tasks carrying a tag
+//	assignee:<person>   tasks assigned to a person, ignoring case
+//	@<context>          tasks doable in a context
+//	<word>              tasks whose description contains the word, ignoring case
+type query struct {
+	terms []queryTerm
+}
+
+func parseQuery(text string, today time.Time) (query, error) {
+	var q query
+	for _, word := range strings.Fields(text) {
+		term := queryTerm{}
+		if strings.HasPrefix(word, "-") && len(word) > 1 {
+			term.negated, word = true, word[1:]
+		}
+		match, err := parseQueryTerm(word, today)
+		if err != nil {
+			return query{}, err
+		}
+		term.match = match
+		q.terms = append(q.terms, term)
+	}
+	if len(q.terms) == 0 {
+		return query{}, fmt.Errorf("empty query")
+	}
+	return q, nil
+}
+
+func parseQueryTerm(word string, today time.Time) (func(ref taskRef) bool, error) {
+	key, value := word, ""
+	if i := strings.Index(word, ":"); i > 0 {
+		key, value = word[:i], word[i+1:]
+	}
+	switch {
+	case word == "all":
+		return func(taskRef) bool { return true }, nil
+	case word == "done":
+		return func(ref taskRef) bool { return ref.task.IsDone() }, nil
+	case word == "pending":
+		return func(ref taskRef) bool { return !ref.task.IsDone() }, nil
+	case word == "overdue":
+		return func(ref taskRef) bool {
+			due, ok := ref.task.deadline.Date()
+			return ok && !ref.task.IsDone() && due.Before(today)
+		}, nil
+	case word == "today":
+		return func(ref taskRef) bool { return !ref.task.IsDone() && ref.task.IsDueOn(today) }, nil
+	case key == "project":
+		return func(ref taskRef) bool { return sameProject(ref.project, value) }, nil
+	case key == "size":
+		s, ok := parseSize(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid size %q", value)
+		}
+		return func(ref taskRef) bool { return ref.task.size == s }, nil
+	case key == "goal":
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid goal %q", value)
+		}
+		return func(ref taskRef) bool { return ref.task.goal == id }, nil
+	case key == "tag":
+		return func(ref taskRef) bool { return ref.task.HasTag(value) }, nil
+	case key == "assignee":
+		return func(ref taskRef) bool { return ref.task.IsAssignedTo(value) }, nil
+	case isContext(word):
+		return func(ref taskRef) bool { return ref.task.HasContext(word) }, nil
+	}
+	lower := strings.ToLower(word)
+	return func(ref taskRef) bool {
+		return strings.Contains(strings.ToLower(ref.task.GetDescription()), lower)
+	}, nil
+}
+
+// Matches returns whether the task satisfies every term of the query.
+func (q query) Matches(ref taskRef) bool {
+	for _, term := range q.terms {
+		if term.match(ref) == term.negated {
+			return false
+		}
+	}
+	return true
+}
+
+// findTasks returns the tasks matching the query, ordered by project.
+func (l *TaskList) findTasks(q query) []taskRef {
+	var refs []taskRef
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			if ref := (taskRef{project: project, task: task}); q.Matches(ref) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
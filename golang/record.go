@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	recordUsage = "could not execute record. Usage: record <file> | record stop"
+	replayUsage = "could not execute replay. Usage: replay <file|->"
+)
+
+// record starts writing every command line typed, with the time it was run, to a file
+// that replay can run again. "record stop" closes the file.
+func (l *TaskList) record(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf(recordUsage)
+	}
+	if args[0] == "stop" {
+		if l.recording == nil {
+			fmt.Fprintln(l.out, "Not recording.")
+			return nil
+		}
+		l.stopRecording()
+		return nil
+	}
+	if l.recording != nil {
+		l.stopRecording()
+	}
+	file, err := os.Create(args[0])
+	if err != nil {
+		l.fail(codeExportFailed, "Could not record: %v.", err)
+		return nil
+	}
+	l.recording, l.recordPath = file, args[0]
+	fmt.Fprintf(l.out, "Recording commands to %s.\n", args[0])
+	return nil
+}
+
+func (l *TaskList) stopRecording() {
+	if err := l.recording.Close(); err != nil {
+		l.fail(codeExportFailed, "Could not record: %v.", err)
+	}
+	fmt.Fprintf(l.out, "Stopped recording to %s.\n", l.recordPath)
+	l.recording, l.recordPath = nil, ""
+}
+
+// recordCommand appends a command line to the recording, if any, before it runs,
+// so the recording also holds the command of a crash.
+func (l *TaskList) recordCommand(cmdLine string) {
+	if l.recording == nil || l.replaying {
+		return
+	}
+	if args, _ := tokenize(cmdLine); len(args) == 0 || args[0] == "record" || args[0] == "replay" {
+		return
+	}
+	if _, err := fmt.Fprintf(l.recording, "%s\t%s\n", l.now().Format(time.RFC3339Nano), cmdLine); err != nil {
+		l.fail(codeExportFailed, "Could not record: %v.", err)
+	}
+}
+
+// replay runs the commands of a recording against a fresh list, with the clock set
+// to the time each command was recorded, so a session can be reproduced exactly.
+func (l *TaskList) replay(path string) {
+	file, err := l.openInput(path)
+	if err != nil {
+		l.fail(codeImportFailed, "Could not replay: %v.", err)
+		return
+	}
+	defer file.Close()
+	entries, err := readRecording(file)
+	if err != nil {
+		l.fail(codeImportFailed, "Could not replay: %v.", err)
+		return
+	}
+
+	now, command := l.now, l.command
+	l.replaying = true
+	defer func() { l.now, l.command, l.replaying = now, command, false }()
+	l.reset()
+	for _, entry := range entries {
+		at := entry.at
+		l.now = func() time.Time { return at }
+		if err := l.execute(entry.command); err != nil {
+			l.command = command
+			l.fail(codeImportFailed, "Replay stopped at line %d: %v.", entry.line, err)
+			return
+		}
+	}
+	fmt.Fprintf(l.out, "Replayed %s from %s.\n", countOf(len(entries), "command"), path)
+}
+
+// recordedCommand is a line of a recording.
+type recordedCommand struct {
+	line    int
+	at      time.Time
+	command string
+}
+
+func readRecording(in io.Reader) ([]recordedCommand, error) {
+	var entries []recordedCommand
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: expected a time and a command separated by a tab", lineNumber)
+		}
+		at, err := time.Parse(time.RFC3339Nano, parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time %q", lineNumber, parts[0])
+		}
+		entries = append(entries, recordedCommand{line: lineNumber, at: at.Local(), command: parts[1]})
+	}
+	return entries, scanner.Err()
+}
+
+// reset empties the list and restores the default settings, keeping the session I/O.
+func (l *TaskList) reset() {
+	l.projectTasks = make(map[string][]*Task)
+	l.archived = nil
+	l.goals, l.habits = nil, nil
+	l.lastID, l.chosenIDs = 0, nil
+	l.settings = defaultSettings()
+	l.currentProject = ""
+	l.lastRollover = time.Time{}
+	l.undoStack, l.redoStack, l.pendingUndo = nil, nil, nil
+}
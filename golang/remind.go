@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const remindUsage = "could not execute remind. Usage: remind <task ID> <lead time, e.g. 30m, 2h or 1d>|none"
+
+// reminderInterval is how often the scheduler looks for reminders to give.
+const reminderInterval = time.Minute
+
+// parseLead reads how long before its deadline a task is reminded of, in minutes,
+// hours or days, e.g. "30m", "2h" or "1d".
+func parseLead(value string) (time.Duration, error) {
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid lead time %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	lead, err := time.ParseDuration(value)
+	if err != nil || lead <= 0 {
+		return 0, fmt.Errorf("invalid lead time %q", value)
+	}
+	return lead, nil
+}
+
+// remind sets how long before its deadline a task is reminded of, or removes the reminder.
+func (l *TaskList) remind(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf(remindUsage)
+	}
+	lead := time.Duration(0)
+	if args[1] != "none" {
+		var err error
+		if lead, err = parseLead(args[1]); err != nil {
+			return fmt.Errorf(remindUsage)
+		}
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.remindBefore, task.reminded = lead, false
+	if lead > 0 && task.deadline.IsEmpty() {
+		fmt.Fprintf(l.out, "Task %s has no deadline yet, it will be reminded of once it has one.\n", task.GetID())
+	}
+	return nil
+}
+
+// giveReminders announces the open tasks whose deadline is within their lead time,
+// on the output and through the channels routed for reminders. Each task is reminded once
+// per deadline.
+func (l *TaskList) giveReminders() {
+	now := l.now()
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			if task.IsDone() || task.remindBefore == 0 || task.reminded {
+				continue
+			}
+			due, ok := task.deadline.Due()
+			if !ok || now.Before(due.Add(-task.remindBefore)) {
+				continue
+			}
+			task.reminded = true
+			message := fmt.Sprintf("Reminder: task %s \"%s\" is due %s.", task.GetID(), task.GetDescription(), dueText(task))
+			fmt.Fprintln(l.out, message)
+			l.notify(eventReminder, message)
+		}
+	}
+}
+
+// dueText tells when a task is due, at its time of day or by the end of its day.
+func dueText(task *Task) string {
+	date, _ := task.deadline.Date()
+	if task.deadline.clock != "" {
+		return fmt.Sprintf("at %s %s", date.Format("2006-01-02"), task.deadline.clock)
+	}
+	return "on " + date.Format("2006-01-02")
+}
+
+// reminderDetail describes the reminder of a task for the detailed display.
+func reminderDetail(task *Task) (string, bool) {
+	if task.remindBefore == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("reminder: %s before", formatLead(task.remindBefore)), true
+}
+
+func formatLead(lead time.Duration) string {
+	if day := 24 * time.Hour; lead%day == 0 {
+		return fmt.Sprintf("%dd", lead/day)
+	}
+	return formatMinutes(lead)
+}
+
+// WatchReminders gives the reminders due every interval, between the commands of the session.
+func (l *TaskList) WatchReminders(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.inBackground(l.giveReminders)
+	}
+}
+
+// inBackground runs work started outside of a command, such as a timer, once no command
+// is running. Its output is written on a line of its own, followed by the prompt again.
+func (l *TaskList) inBackground(work func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := l.out
+	buffered := &strings.Builder{}
+	l.out = buffered
+	work()
+	l.out = out
+	if buffered.Len() > 0 {
+		fmt.Fprint(l.out, "\n"+buffered.String()+l.prompt())
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// display is the level of detail used when rendering tasks in every view.
+type display string
+
+const (
+	displayCompact  display = "compact"
+	displayNormal   display = "normal"
+	displayDetailed display = "detailed"
+
+	compactDescriptionLength = 30
+	timestampLayout          = "2006-01-02 15:04"
+)
+
+func parseDisplay(value string) (display, error) {
+	switch d := display(value); d {
+	case displayCompact, displayNormal, displayDetailed:
+		return d, nil
+	}
+	return "", fmt.Errorf("invalid display %q, expected compact, normal or detailed", value)
+}
+
+// renderOptions carries the session state some columns depend on.
+type renderOptions struct {
+	icons           bool
+	today           time.Time
+	agingThresholds []int
+	workdays        workCalendar
+}
+
+// column is a task field that can be displayed in a view.
+type column struct {
+	name   string
+	render func(task *Task, opts renderOptions) string
+}
+
+var columns = []column{
+	{name: "status", render: renderStatus},
+	{name: "id", render: func(task *Task, _ renderOptions) string { return fmt.Sprintf("%s:", task.GetID()) }},
+	{name: "deadline", render: func(task *Task, _ renderOptions) string { return strings.TrimSpace(task.GetDeadline()) }},
+	{name: "description", render: func(task *Task, _ renderOptions) string { return task.GetDescription() }},
+	{name: "age", render: renderAge},
+	{name: "tags", render: renderTags},
+	{name: "blocked", render: renderBlocked},
+	{name: "assignee", render: renderAssignee},
+	{name: "contexts", render: func(task *Task, _ renderOptions) string { return strings.Join(task.GetContexts(), " ") }},
+	{name: "size", render: func(task *Task, _ renderOptions) string { return string(task.size) }},
+	{name: "priority", render: func(task *Task, _ renderOptions) string {
+		if task.priority == priorityNone {
+			return ""
+		}
+		return task.priority.String()
+	}},
+	{name: "estimate", render: renderEstimate},
+}
+
+// displayColumns are the columns shown by default, before the ones only shown on request.
+var displayColumns = columns[:8]
+
+var compactColumns = []column{
+	columns[0],
+	columns[1],
+	{name: "description", render: func(task *Task, _ renderOptions) string {
+		return truncate(task.GetDescription(), compactDescriptionLength)
+	}},
+	columns[4],
+	columns[5],
+	columns[6],
+	columns[7],
+}
+
+// renderStatus returns the checkbox of a task, holding its progress when partly done,
+// or its status icon when icons are enabled.
+func renderStatus(task *Task, opts renderOptions) string {
+	if !opts.icons {
+		if task.IsDone() {
+			return "[X]"
+		}
+		if task.percent > 0 {
+			return fmt.Sprintf("[%d%%]", task.percent)
+		}
+		return "[ ]"
+	}
+	due, hasDate := task.deadline.Date()
+	switch {
+	case task.IsDone():
+		return "✅"
+	case hasDate && due.Before(opts.today):
+		return "🔥"
+	case task.IsDueOn(opts.today):
+		return "⏰"
+	}
+	return "⬜"
+}
+
+// taskTable renders tasks as rows of cells, one per selected column.
+// A table using the display columns keeps the historical compact layout;
+// a table with user-selected columns aligns its cells.
+type taskTable struct {
+	columns []column
+	options renderOptions
+	aligned bool
+	details func(task *Task) []string
+	rows    []tableRow
+}
+
+// tableRow holds the cells of a task, followed by its detail lines.
+type tableRow struct {
+	cells   []string
+	details []string
+	// depth is the nesting level of a subtask under its parents.
+	depth int
+}
+
+// newDisplayTaskTable creates a table rendering tasks according to the display setting.
+func newDisplayTaskTable(d display, opts renderOptions) *taskTable {
+	switch d {
+	case displayCompact:
+		return &taskTable{columns: compactColumns, options: opts}
+	case displayDetailed:
+		return &taskTable{columns: displayColumns, options: opts, details: taskDetails}
+	}
+	return &taskTable{columns: displayColumns, options: opts}
+}
+
+// newTaskTable creates a table showing the named columns, in the given order.
+func newTaskTable(names []string, opts renderOptions) (*taskTable, error) {
+	table := &taskTable{options: opts, aligned: true}
+	for _, name := range names {
+		col, ok := columnNamed(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		table.columns = append(table.columns, col)
+	}
+	return table, nil
+}
+
+func columnNamed(name string) (column, bool) {
+	for _, col := range columns {
+		if col.name == name {
+			return col, true
+		}
+	}
+	return column{}, false
+}
+
+// Add appends the row of a task to the table, followed by any extra cells.
+func (t *taskTable) Add(task *Task, extra ...string) {
+	t.AddIndented(task, 0, extra...)
+}
+
+// AddIndented appends the row of a task nested depth levels under its parents.
+func (t *taskTable) AddIndented(task *Task, depth int, extra ...string) {
+	row := tableRow{cells: make([]string, 0, len(t.columns)+len(extra)), depth: depth}
+	for _, col := range t.columns {
+		row.cells = append(row.cells, col.render(task, t.options))
+	}
+	row.cells = append(row.cells, extra...)
+	if t.details != nil {
+		row.details = t.details(task)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Flush writes the indented rows added so far, then empties the table.
+func (t *taskTable) Flush(out io.Writer) {
+	var widths []int
+	if t.aligned {
+		for _, row := range t.rows {
+			for i, cell := range row.cells {
+				if i == len(widths) {
+					widths = append(widths, 0)
+				}
+				if width := displayWidth(cell); width > widths[i] {
+					widths[i] = width
+				}
+			}
+		}
+	}
+	for _, row := range t.rows {
+		indent := strings.Repeat("  ", row.depth)
+		fmt.Fprintf(out, "    %s%s\n", indent, t.format(row.cells, widths))
+		for _, detail := range row.details {
+			fmt.Fprintf(out, "        %s%s\n", indent, detail)
+		}
+	}
+	t.rows = t.rows[:0]
+}
+
+func (t *taskTable) format(row []string, widths []int) string {
+	cells := make([]string, 0, len(row))
+	for i, cell := range row {
+		if !t.aligned {
+			if cell != "" {
+				cells = append(cells, cell)
+			}
+			continue
+		}
+		if i < len(row)-1 {
+			cell = padRight(cell, widths[i])
+		}
+		cells = append(cells, cell)
+	}
+	return strings.TrimRight(strings.Join(cells, " "), " ")
+}
+
+// taskDetails returns the extra lines shown under a task in the detailed display.
+func taskDetails(task *Task) []string {
+	details := []string{
+		"created: " + task.GetCreated().Format(timestampLayout),
+	}
+	if contexts := task.GetContexts(); len(contexts) > 0 {
+		details = append(details, "contexts: "+strings.Join(contexts, " "))
+	}
+	if task.size != sizeNone {
+		details = append(details, "size: "+string(task.size))
+	}
+	if task.priority != priorityNone {
+		details = append(details, "priority: "+task.priority.String())
+	}
+	if rollovers, ok := rolloverDetail(task); ok {
+		details = append(details, rollovers)
+	}
+	if reminder, ok := reminderDetail(task); ok {
+		details = append(details, reminder)
+	}
+	if estimate, ok := estimateDetail(task); ok {
+		details = append(details, estimate)
+	}
+	if spent, ok := spentDetail(task); ok {
+		details = append(details, spent)
+	}
+	if pomodoros, ok := pomodoroDetail(task); ok {
+		details = append(details, pomodoros)
+	}
+	return append(details, noteDetails(task)...)
+}
+
+// iconMode tells whether task statuses are rendered as emoji icons.
+type iconMode string
+
+const (
+	iconsOn   iconMode = "on"
+	iconsOff  iconMode = "off"
+	iconsAuto iconMode = "auto"
+)
+
+func parseIconMode(value string) (iconMode, error) {
+	switch m := iconMode(value); m {
+	case iconsOn, iconsOff, iconsAuto:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid icons %q, expected on, off or auto", value)
+}
+
+// Enabled returns whether icons should be written to out. In auto mode, icons are only
+// used on terminals with a UTF-8 locale, and plain ASCII is kept for pipes and files.
+func (m iconMode) Enabled(out io.Writer) bool {
+	switch m {
+	case iconsOn:
+		return true
+	case iconsAuto:
+		return isTerminal(out) && hasUTF8Locale()
+	}
+	return false
+}
+
+// isTerminal returns whether the given descriptor is an interactive terminal.
+func isTerminal(descriptor interface{}) bool {
+	file, ok := descriptor.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func hasUTF8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToUpper(value)
+			return strings.Contains(value, "UTF-8") || strings.Contains(value, "UTF8")
+		}
+	}
+	return false
+}
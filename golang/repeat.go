@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const repeatUsage = "could not execute repeat. Usage: repeat <task ID> daily|weekly|monthly|none"
+
+// monthly tasks come back on the same day of the next month, or on its last day when shorter.
+const monthly frequency = "monthly"
+
+func (l *TaskList) repeat(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf(repeatUsage)
+	}
+	f := frequency(args[1])
+	switch f {
+	case daily, weekly, monthly:
+	case "none":
+		f = ""
+	default:
+		return fmt.Errorf(repeatUsage)
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.recurrence = f
+	return nil
+}
+
+// next returns the day after from when a task of this frequency comes back.
+func (f frequency) next(from time.Time) time.Time {
+	switch f {
+	case weekly:
+		return from.AddDate(0, 0, 7)
+	case monthly:
+		firstOfNext := time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, from.Location())
+		lastDay := firstOfNext.AddDate(0, 1, -1).Day()
+		return firstOfNext.AddDate(0, 0, minInt(from.Day(), lastDay)-1)
+	}
+	return from.AddDate(0, 0, 1)
+}
+
+// complete checks or unchecks a task. Checking a recurring task creates its next
+// occurrence in the same project, due one period after its deadline, or after today
+// when it has no deadline date.
+func (l *TaskList) complete(task *Task, done bool) {
+	if done && l.hasOpenSubtasks(task) {
+		l.fail(codeOpenSubtasks, "Task %s still has open subtasks, check them first.", task.GetID())
+		return
+	}
+	if done && !task.IsDone() && len(task.OpenBlockers()) > 0 {
+		l.fail(codeBlocked, "Task %s is %s.", task.GetID(), blockedBy(task))
+		return
+	}
+	wasDone := task.IsDone()
+	l.markDone(task, done)
+	if done && !wasDone {
+		l.notify(eventDone, fmt.Sprintf("Task %s done: %s", task.GetID(), task.GetDescription()))
+	}
+	if !done {
+		l.reopenParents(task)
+	}
+	if !done || wasDone || task.recurrence == "" {
+		return
+	}
+	project := l.projectOf(task)
+	from, ok := task.deadline.Date()
+	if !ok {
+		from = startOfDay(l.now())
+	}
+	due := task.recurrence.next(from)
+	next := l.addTaskWithID(project, "", task.GetDescription())
+	d, _ := NewDeadline(due.Format(deadlineLayout))
+	d.clock = task.deadline.clock
+	next.SetDeadline(d)
+	next.recurrence, next.size, next.priority = task.recurrence, task.size, task.priority
+	next.SetTags(task.GetTags(), true)
+	if contexts := task.GetContexts(); len(contexts) > 0 {
+		next.contexts = map[string]bool{}
+		for _, context := range contexts {
+			next.contexts[context] = true
+		}
+	}
+	fmt.Fprintf(l.out, "Next occurrence: task %s due %s.\n", next.GetID(), due.Format("2006-01-02"))
+}
+
+// projectOf returns the name of the project holding the task.
+func (l *TaskList) projectOf(task *Task) string {
+	for project, tasks := range l.projectTasks {
+		for _, t := range tasks {
+			if t == task {
+				return project
+			}
+		}
+	}
+	return ""
+}
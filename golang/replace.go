@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const replaceUsage = `could not execute replace. Usage: replace [--yes] <query> "<old>" "<new>"`
+
+// replaceText replaces a piece of text in the descriptions of the tasks matching a query.
+// The changes are previewed and only made once confirmed, or straight away with --yes.
+func (l *TaskList) replaceText(args []string) error {
+	confirmed := false
+	if len(args) > 0 && args[0] == "--yes" {
+		confirmed, args = true, args[1:]
+	}
+	if len(args) != 3 || args[1] == "" {
+		return fmt.Errorf(replaceUsage)
+	}
+	old, replacement := args[1], args[2]
+	q, err := parseQuery(args[0], startOfDay(l.now()))
+	if err != nil {
+		l.fail(codeInvalidQuery, "Invalid query \"%s\": %v.", args[0], err)
+		return nil
+	}
+
+	var refs []taskRef
+	for _, ref := range l.findTasks(q) {
+		if strings.Contains(ref.task.GetDescription(), old) {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		fmt.Fprintf(l.out, "No matching task contains \"%s\".\n", old)
+		return nil
+	}
+	for _, ref := range refs {
+		fmt.Fprintf(l.out, "    %s: %s\n", ref.task.GetID(), ref.task.GetDescription())
+		fmt.Fprintf(l.out, "    %s  %s\n", strings.Repeat(" ", displayWidth(string(ref.task.GetID()))), strings.ReplaceAll(ref.task.GetDescription(), old, replacement))
+	}
+	if !confirmed && !l.confirm(fmt.Sprintf("Replace in %s?", countOf(len(refs), "task"))) {
+		fmt.Fprintln(l.out, "Nothing replaced.")
+		return nil
+	}
+	for _, ref := range refs {
+		ref.task.description = strings.ReplaceAll(ref.task.GetDescription(), old, replacement)
+	}
+	fmt.Fprintf(l.out, "Replaced \"%s\" with \"%s\" in %s.\n", old, replacement, countOf(len(refs), "task"))
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+)
+
+// rollover moves the deadline of every overdue open task to today, counting on each
+// task how many times it was rolled over, like a bullet-journal migration.
+func (l *TaskList) rollover() int {
+	today := startOfDay(l.now())
+	moved := 0
+	for _, tasks := range l.projectTasks {
+		for _, task := range tasks {
+			due, ok := task.deadline.Date()
+			if !ok || task.IsDone() || !due.Before(today) {
+				continue
+			}
+			deadline, err := NewDeadline(today.Format(deadlineLayout))
+			if err != nil {
+				continue
+			}
+			task.SetDeadline(deadline)
+			task.rollovers++
+			moved++
+		}
+	}
+	l.lastRollover = today
+	return moved
+}
+
+func (l *TaskList) rolloverCommand() {
+	fmt.Fprintf(l.out, "Rolled over %s to today.\n", countOf(l.rollover(), "task"))
+}
+
+// autoRollover runs the rollover once a day, before the first command of the day,
+// when the auto-rollover setting is on.
+func (l *TaskList) autoRollover() {
+	if !l.settings.autoRollover || !l.now().After(l.lastRollover.AddDate(0, 0, 1)) {
+		return
+	}
+	if moved := l.rollover(); moved > 0 {
+		fmt.Fprintf(l.out, "Rolled over %s to today.\n", countOf(moved, "overdue task"))
+	}
+}
+
+// rolloverDetail describes how many times a task was rolled over, for the detailed display.
+func rolloverDetail(task *Task) (string, bool) {
+	switch task.rollovers {
+	case 0:
+		return "", false
+	case 1:
+		return "rolled-over: once", true
+	}
+	return fmt.Sprintf("rolled-over: %d times", task.rollovers), true
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// search prints the tasks whose description or project name contains the text, ignoring case.
+func (l *TaskList) search(text string) {
+	needle := strings.ToLower(text)
+	var refs []taskRef
+	for _, project := range l.sortedProjects() {
+		inProject := strings.Contains(strings.ToLower(project), needle)
+		for _, task := range l.projectTasks[project] {
+			if inProject || strings.Contains(strings.ToLower(task.GetDescription()), needle) {
+				refs = append(refs, taskRef{project: project, task: task})
+			}
+		}
+	}
+	if len(refs) == 0 {
+		fmt.Fprintf(l.out, "No task matches \"%s\".\n", text)
+		return
+	}
+	l.printAgendaSection(fmt.Sprintf("%s matching \"%s\":", capitalize(countOf(len(refs), "task")), text), refs)
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// settings holds the user preferences changed with the set command.
+type settings struct {
+	progressBars bool
+	footer       bool
+	display      display
+	icons        iconMode
+	workspace    string
+	promptText   string
+	prompt       *template.Template
+	inbox        string
+	descriptions descriptionPipeline
+	workdays     workCalendar
+	autoRollover bool
+	// agingThresholds are the ages in working days from which open tasks get an age marker.
+	agingThresholds []int
+	// priorityAging are the ages in working days from which open tasks rise one priority level.
+	priorityAging []int
+	// quitSummary is set to review the day when the session ends.
+	quitSummary bool
+	// telemetry is set when the user agreed to share anonymous usage counts.
+	telemetry bool
+	// capacityHours is the estimated work that fits in a day, 0 to disable.
+	capacityHours int
+	// maxPerDay is the number of open tasks due on a single day above which a warning is given, 0 to disable.
+	maxPerDay int
+	// notifyRoutes lists, for each event, the channels notified of it.
+	notifyRoutes map[string][]string
+	webhookURL   string
+	slackURL     string
+	// smtpServer is the host:port of the server mailing notifications to emailTo.
+	smtpServer    string
+	emailTo       string
+	telegramToken string
+	telegramChat  string
+}
+
+func defaultSettings() settings {
+	s := settings{
+		display:         displayNormal,
+		icons:           iconsOff,
+		workspace:       "default",
+		workdays:        defaultWorkCalendar(),
+		agingThresholds: []int{7, 14, 30},
+		maxPerDay:       5,
+	}
+	s.Set("prompt", prompt)
+	return s
+}
+
+// setting describes how a preference is read and written from its textual form.
+type setting struct {
+	get func(s *settings) string
+	set func(s *settings, value string) error
+}
+
+var settingsByKey = map[string]setting{
+	"aging": {
+		get: func(s *settings) string { return formatAgingThresholds(s.agingThresholds) },
+		set: func(s *settings, value string) error {
+			thresholds, err := parseAgingThresholds(value)
+			if err != nil {
+				return err
+			}
+			s.agingThresholds = thresholds
+			return nil
+		},
+	},
+	"auto-rollover": {
+		get: func(s *settings) string { return formatSwitch(s.autoRollover) },
+		set: func(s *settings, value string) error { return parseSwitch(value, &s.autoRollover) },
+	},
+	"bars": {
+		get: func(s *settings) string { return formatSwitch(s.progressBars) },
+		set: func(s *settings, value string) error { return parseSwitch(value, &s.progressBars) },
+	},
+	"capacity": {
+		get: func(s *settings) string {
+			if s.capacityHours == 0 {
+				return "off"
+			}
+			return strconv.Itoa(s.capacityHours) + "h"
+		},
+		set: func(s *settings, value string) error {
+			if value == "off" {
+				s.capacityHours = 0
+				return nil
+			}
+			return parseCount(strings.TrimSuffix(value, "h"), &s.capacityHours)
+		},
+	},
+	"display": {
+		get: func(s *settings) string { return string(s.display) },
+		set: func(s *settings, value string) error {
+			display, err := parseDisplay(value)
+			if err != nil {
+				return err
+			}
+			s.display = display
+			return nil
+		},
+	},
+	"holidays": {
+		get: func(s *settings) string { return s.workdays.holidaysFile },
+		set: func(s *settings, value string) error { return s.workdays.LoadHolidays(value) },
+	},
+	"icons": {
+		get: func(s *settings) string { return string(s.icons) },
+		set: func(s *settings, value string) error {
+			icons, err := parseIconMode(value)
+			if err != nil {
+				return err
+			}
+			s.icons = icons
+			return nil
+		},
+	},
+	"footer": {
+		get: func(s *settings) string { return formatSwitch(s.footer) },
+		set: func(s *settings, value string) error { return parseSwitch(value, &s.footer) },
+	},
+	"max-per-day": {
+		get: func(s *settings) string { return strconv.Itoa(s.maxPerDay) },
+		set: func(s *settings, value string) error { return parseCount(value, &s.maxPerDay) },
+	},
+	"notify": {
+		get: func(s *settings) string { return formatNotifyRoutes(s.notifyRoutes) },
+		set: func(s *settings, value string) error {
+			routes, err := parseNotifyRoutes(value)
+			if err != nil {
+				return err
+			}
+			s.notifyRoutes = routes
+			return nil
+		},
+	},
+	"normalize": {
+		get: func(s *settings) string { return s.descriptions.StepsString() },
+		set: func(s *settings, value string) error { return s.descriptions.SetSteps(value) },
+	},
+	"slack-url": {
+		get: func(s *settings) string { return s.slackURL },
+		set: func(s *settings, value string) error {
+			s.slackURL = value
+			return nil
+		},
+	},
+	"smtp-server": {
+		get: func(s *settings) string { return s.smtpServer },
+		set: func(s *settings, value string) error {
+			s.smtpServer = value
+			return nil
+		},
+	},
+	"email-to": {
+		get: func(s *settings) string { return s.emailTo },
+		set: func(s *settings, value string) error {
+			s.emailTo = value
+			return nil
+		},
+	},
+	"telegram-token": {
+		get: func(s *settings) string { return s.telegramToken },
+		set: func(s *settings, value string) error {
+			s.telegramToken = value
+			return nil
+		},
+	},
+	"telegram-chat": {
+		get: func(s *settings) string { return s.telegramChat },
+		set: func(s *settings, value string) error {
+			s.telegramChat = value
+			return nil
+		},
+	},
+	"webhook-url": {
+		get: func(s *settings) string { return s.webhookURL },
+		set: func(s *settings, value string) error {
+			s.webhookURL = value
+			return nil
+		},
+	},
+	"prompt": {
+		get: func(s *settings) string { return fmt.Sprintf("%q", s.promptText) },
+		set: func(s *settings, value string) error {
+			tmpl, err := template.New("prompt").Parse(value)
+			if err != nil {
+				return fmt.Errorf("invalid prompt template: %v", err)
+			}
+			s.promptText, s.prompt = value, tmpl
+			return nil
+		},
+	},
+	"inbox": {
+		get: func(s *settings) string { return s.inbox },
+		set: func(s *settings, value string) error {
+			s.inbox = value
+			return nil
+		},
+	},
+	"priority-aging": {
+		get: func(s *settings) string { return formatAgingThresholds(s.priorityAging) },
+		set: func(s *settings, value string) error {
+			thresholds, err := parseAgingThresholds(value)
+			if err != nil {
+				return err
+			}
+			s.priorityAging = thresholds
+			return nil
+		},
+	},
+	"quit-summary": {
+		get: func(s *settings) string { return formatSwitch(s.quitSummary) },
+		set: func(s *settings, value string) error { return parseSwitch(value, &s.quitSummary) },
+	},
+	"replace": {
+		get: func(s *settings) string { return s.descriptions.ReplacementsString() },
+		set: func(s *settings, value string) error { return s.descriptions.AddReplacement(value) },
+	},
+	"telemetry": {
+		get: func(s *settings) string { return formatSwitch(s.telemetry) },
+		set: func(s *settings, value string) error { return parseSwitch(value, &s.telemetry) },
+	},
+	"weekend": {
+		get: func(s *settings) string { return s.workdays.WeekendString() },
+		set: func(s *settings, value string) error { return s.workdays.SetWeekend(value) },
+	},
+	"workspace": {
+		get: func(s *settings) string { return s.workspace },
+		set: func(s *settings, value string) error {
+			s.workspace = value
+			return nil
+		},
+	},
+}
+
+// Set changes the preference named key to the given textual value.
+func (s *settings) Set(key, value string) error {
+	setting, ok := settingsByKey[key]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return setting.set(s, value)
+}
+
+// Keys returns the names of every known preference, sorted alphabetically.
+func (s *settings) Keys() []string {
+	keys := make([]string, 0, len(settingsByKey))
+	for key := range settingsByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the textual value of the preference named key.
+func (s *settings) Get(key string) string {
+	return settingsByKey[key].get(s)
+}
+
+func formatSwitch(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+func parseSwitch(value string, target *bool) error {
+	switch value {
+	case "on":
+		*target = true
+	case "off":
+		*target = false
+	default:
+		return fmt.Errorf("invalid value %q, expected on or off", value)
+	}
+	return nil
+}
+
+func parseCount(value string, target *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid value %q, expected a positive number or 0", value)
+	}
+	*target = n
+	return nil
+}
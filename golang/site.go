@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var siteTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<nav><a href="{{.Root}}index.html">Projects</a> | <a href="{{.Root}}today.html">Today</a> | <a href="{{.Root}}overdue.html">Overdue</a></nav>
+<h1>{{.Title}}</h1>
+{{- if .Projects}}
+<ul>
+{{- range .Projects}}
+<li><a href="{{.Page}}">{{.Name}}</a> ({{.Progress}}) <a href="{{.Feed}}">feed</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Tasks}}
+<table>
+<tr><th>Status</th><th>ID</th><th>Deadline</th><th>Description</th><th>Project</th></tr>
+{{- range .Tasks}}
+<tr><td>{{.Status}}</td><td>{{.ID}}</td><td>{{.Deadline}}</td><td>{{.Description}}</td><td>{{.Project}}</td></tr>
+{{- end}}
+</table>
+{{- else if not .Projects}}
+<p>Nothing to show.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type sitePage struct {
+	Title    string
+	Root     string
+	Projects []siteProject
+	Tasks    []siteTask
+}
+
+type siteProject struct {
+	Name     string
+	Page     string
+	Feed     string
+	Progress string
+}
+
+type siteTask struct {
+	Status      string
+	ID          string
+	Deadline    string
+	Description string
+	Project     string
+}
+
+// exportSite generates a static HTML site in dir: an index of the projects, a page per
+// project with its Atom feed, and pages listing the tasks due today and the overdue ones.
+// Only the tasks matching where are published, and the projects left without any are skipped.
+func (l *TaskList) exportSite(dir string, where query) {
+	if err := l.writeSite(dir, where); err != nil {
+		l.fail(codeExportFailed, "Could not export site: %v.", err)
+		return
+	}
+	fmt.Fprintf(l.out, "Exported site to %s.\n", dir)
+}
+
+func (l *TaskList) writeSite(dir string, where query) error {
+	for _, sub := range []string{"projects", "feeds"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	index := sitePage{Title: "Projects"}
+	// projects such as "C" and "C++" share a slug, numbered to keep their files apart
+	slugs := map[string]bool{}
+	for _, project := range l.sortedProjects() {
+		tasks := l.scopedTasks(project, where)
+		if len(tasks) == 0 && len(where.terms) > 0 {
+			continue
+		}
+		slug := slugify(project)
+		for n := 2; slugs[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", slugify(project), n)
+		}
+		slugs[slug] = true
+		index.Projects = append(index.Projects, siteProject{
+			Name:     project,
+			Page:     "projects/" + slug + ".html",
+			Feed:     "feeds/" + slug + ".xml",
+			Progress: progressOf(tasks).String(),
+		})
+
+		var refs []taskRef
+		for _, task := range tasks {
+			refs = append(refs, taskRef{project: project, task: task})
+		}
+		page := sitePage{Title: project, Root: "../", Tasks: siteTasks(refs)}
+		if err := writeSitePage(filepath.Join(dir, "projects", slug+".html"), page); err != nil {
+			return err
+		}
+		if err := writeFeedFile(filepath.Join(dir, "feeds", slug+".xml"), project, tasks, l.now()); err != nil {
+			return err
+		}
+	}
+	if err := writeSitePage(filepath.Join(dir, "index.html"), index); err != nil {
+		return err
+	}
+
+	today := startOfDay(l.now())
+	var dueToday, overdue []taskRef
+	for _, ref := range l.openTasksByDeadline() {
+		if !where.Matches(ref) {
+			continue
+		}
+		due, _ := ref.task.deadline.Date()
+		if due.Before(today) {
+			overdue = append(overdue, ref)
+		} else if ref.task.IsDueOn(today) {
+			dueToday = append(dueToday, ref)
+		}
+	}
+	if err := writeSitePage(filepath.Join(dir, "today.html"), sitePage{Title: "Today", Tasks: siteTasks(dueToday)}); err != nil {
+		return err
+	}
+	return writeSitePage(filepath.Join(dir, "overdue.html"), sitePage{Title: "Overdue", Tasks: siteTasks(overdue)})
+}
+
+func writeFeedFile(path, project string, tasks []*Task, now time.Time) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeFeed(file, project, tasks, now)
+}
+
+func writeSitePage(path string, page sitePage) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return siteTemplate.Execute(file, page)
+}
+
+func siteTasks(refs []taskRef) []siteTask {
+	tasks := make([]siteTask, 0, len(refs))
+	for _, ref := range refs {
+		tasks = append(tasks, siteTask{
+			Status:      renderStatus(ref.task, renderOptions{}),
+			ID:          string(ref.task.GetID()),
+			Deadline:    strings.Trim(ref.task.GetDeadline(), " ()"),
+			Description: ref.task.GetDescription(),
+			Project:     ref.project,
+		})
+	}
+	return tasks
+}
+
+// slugify turns a project name into a file name made of lowercase letters, digits and dashes.
+func slugify(name string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && slug.Len() > 0 {
+				slug.WriteRune('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if slug.Len() == 0 {
+		return "project"
+	}
+	return slug.String()
+}
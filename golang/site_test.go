@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportSite(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project \"Secret plans\"",
+		"add task \"Secret plans\" Eat more donuts.",
+		"add task \"Secret plans\" Destroy <all> humans.",
+		"deadline 1 20240610",
+		"deadline 2 20240601",
+	)
+	out.Reset()
+	dir := t.TempDir()
+
+	executeAll(t, l, "export site "+dir)
+	assertOutput(t, out, "Exported site to "+dir+".")
+
+	for _, page := range []struct {
+		path     string
+		contains string
+	}{
+		{"index.html", `<li><a href="projects/secret-plans.html">Secret plans</a> (0/2, 0%) <a href="feeds/secret-plans.xml">feed</a></li>`},
+		{"projects/secret-plans.html", `<td>Destroy &lt;all&gt; humans.</td>`},
+		{"today.html", `<tr><td>[ ]</td><td>1</td><td>20240610</td><td>Eat more donuts.</td><td>Secret plans</td></tr>`},
+		{"overdue.html", `<td>Destroy &lt;all&gt; humans.</td>`},
+		{"feeds/secret-plans.xml", `<id>urn:task-list:project:Secret plans</id>`},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, page.path))
+		if err != nil {
+			t.Fatalf("%s: %v", page.path, err)
+		}
+		if !strings.Contains(string(content), page.contains) {
+			t.Errorf("%s: expected to contain %q, got:\n%s", page.path, page.contains, content)
+		}
+	}
+}
+
+func TestExportSiteKeepsSlugsApart(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l, "add project C", "add project C++", "add task C++ Read the standard")
+	out.Reset()
+	dir := t.TempDir()
+
+	executeAll(t, l, "export site "+dir)
+	page, err := os.ReadFile(filepath.Join(dir, "projects", "c-2.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "Read the standard") {
+		t.Errorf("expected the task of C++ on its own page, got:\n%s", page)
+	}
+}
+
+func TestExportWhere(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project work",
+		"add project home",
+		"add task work Review PR",
+		"add task work Deploy",
+		"add task home Fix the bike",
+		"check 2",
+	)
+	out.Reset()
+	dir := t.TempDir()
+
+	executeAll(t, l, `export site `+dir+` --where "project:work pending"`)
+	assertOutput(t, out, "Exported site to "+dir+".")
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), `work</a> (0/1, 0%)`) || strings.Contains(string(index), "home") {
+		t.Errorf("expected only the open tasks of work in the index, got:\n%s", index)
+	}
+	page, err := os.ReadFile(filepath.Join(dir, "projects", "work.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "Review PR") || strings.Contains(string(page), "Deploy") {
+		t.Errorf("expected only the open task in the project page, got:\n%s", page)
+	}
+
+	executeAll(t, l, "export feed work - --where done")
+	if feed := out.String(); !strings.Contains(feed, "Deploy") || strings.Contains(feed, "Review PR") {
+		t.Errorf("expected only the done task in the feed, got:\n%s", feed)
+	}
+	out.Reset()
+
+	executeAll(t, l, "export site "+dir+" --where size:XL")
+	assertOutput(t, out, `Invalid query "size:XL": invalid size "XL".`)
+}
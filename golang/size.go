@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const sizeUsage = "could not execute size. Usage: size <task ID> S|M|L|none"
+
+// size is the rough effort a task needs: small, medium or large.
+type size string
+
+const (
+	sizeNone   size = ""
+	sizeSmall  size = "S"
+	sizeMedium size = "M"
+	sizeLarge  size = "L"
+)
+
+func parseSize(value string) (size, bool) {
+	switch s := size(strings.ToUpper(value)); s {
+	case sizeSmall, sizeMedium, sizeLarge:
+		return s, true
+	}
+	if value == "none" {
+		return sizeNone, true
+	}
+	return sizeNone, false
+}
+
+func (l *TaskList) setSize(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf(sizeUsage)
+	}
+	s, ok := parseSize(args[1])
+	if !ok {
+		return fmt.Errorf(sizeUsage)
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.size = s
+	return nil
+}
+
+// isQuickWin returns whether the task is open and small enough to fill a short gap.
+func isQuickWin(task *Task) bool {
+	return !task.IsDone() && task.size == sizeSmall
+}
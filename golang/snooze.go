@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const snoozeUsage = "could not execute snooze. Usage: snooze <task ID> <N>d|<N>w|<N>bd"
+
+// snoozeDuration matches how long a deadline is pushed back: days, weeks or business days.
+var snoozeDuration = regexp.MustCompile(`^(\d+)(d|w|bd)$`)
+
+// snooze pushes back the deadline of a task, counting from its current deadline date,
+// or from today when it has none.
+func (l *TaskList) snooze(idString, duration string) error {
+	match := snoozeDuration.FindStringSubmatch(duration)
+	if match == nil {
+		return fmt.Errorf(snoozeUsage)
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return fmt.Errorf(snoozeUsage)
+	}
+	task, err := l.getTaskBy(idString)
+	if err != nil {
+		return nil
+	}
+
+	from, ok := task.deadline.Date()
+	if !ok {
+		from = startOfDay(l.now())
+	}
+	var due time.Time
+	switch match[2] {
+	case "d":
+		due = from.AddDate(0, 0, n)
+	case "w":
+		due = from.AddDate(0, 0, 7*n)
+	case "bd":
+		due = l.settings.workdays.AddBusinessDays(from, n)
+	}
+	d, err := NewDeadline(due.Format(deadlineLayout))
+	if err != nil {
+		return err
+	}
+	d.clock = task.deadline.clock
+	task.SetDeadline(d)
+	fmt.Fprintf(l.out, "Snoozed task %s until %s.\n", task.GetID(), due.Format("2006-01-02"))
+	l.warnDeadlineLoad(task)
+	return nil
+}
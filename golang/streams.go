@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// streamPath is the file name standing for the standard input or output of the program.
+const streamPath = "-"
+
+// openInput opens the file an importer reads from, "-" being the input of the task list.
+func (l *TaskList) openInput(path string) (io.ReadCloser, error) {
+	if path == streamPath {
+		// read through the buffer of the command loop, which may hold what comes next
+		return io.NopCloser(l.input().reader), nil
+	}
+	return os.Open(path)
+}
+
+// createOutput creates the file an exporter writes to, "-" being the output of the task list.
+func (l *TaskList) createOutput(path string) (io.WriteCloser, error) {
+	if path == streamPath {
+		return nopWriteCloser{l.out}, nil
+	}
+	return os.Create(path)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// lineReader reads the input line by line, as a bufio.Scanner does, but without reading
+// past the buffer of its reader, so that importers reading "-" go on from the next line.
+type lineReader struct {
+	reader *bufio.Reader
+	line   string
+	done   bool
+	err    error
+}
+
+// Scan reads the next line, returning false at the end of the input or on an error.
+func (r *lineReader) Scan() bool {
+	if r.done {
+		return false
+	}
+	line, err := r.reader.ReadString('\n')
+	if err != nil {
+		r.done = true
+		if err != io.EOF {
+			r.err = err
+		}
+		if line == "" {
+			return false
+		}
+	}
+	r.line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	return true
+}
+
+// Text returns the line last read, without its line ending.
+func (r *lineReader) Text() string {
+	return r.line
+}
+
+// Err returns the error that stopped the reading, nil at the end of the input.
+func (r *lineReader) Err() error {
+	return r.err
+}
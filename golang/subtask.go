@@ -0,0 +1,71 @@
+package main
+
+// addSubtask creates a task in the project of its parent, nested under it.
+func (l *TaskList) addSubtask(parentID, description string) {
+	parent, err := l.getTaskBy(parentID)
+	if err != nil {
+		return
+	}
+	if task := l.addTask(l.projectOf(parent), description); task != nil {
+		task.parent = parent.GetID()
+		if parent.IsDone() {
+			l.markDone(parent, false)
+		}
+	}
+}
+
+// hasOpenSubtasks returns whether a subtask of the task is still unchecked.
+func (l *TaskList) hasOpenSubtasks(task *Task) bool {
+	return l.anyTask(func(t *Task) bool { return t.parent == task.GetID() && !t.IsDone() })
+}
+
+// reopenParents unchecks the parents of a reopened subtask, as they are no longer done.
+func (l *TaskList) reopenParents(task *Task) {
+	for task.parent != "" {
+		parent := l.findTask(task.parent)
+		if parent == nil || !parent.IsDone() {
+			return
+		}
+		l.markDone(parent, false)
+		task = parent
+	}
+}
+
+// orphanSubtasks turns the subtasks of a deleted task into top-level tasks.
+func (l *TaskList) orphanSubtasks(task *Task) {
+	for _, tasks := range l.projectTasks {
+		for _, t := range tasks {
+			if t.parent == task.GetID() {
+				t.parent = ""
+			}
+		}
+	}
+}
+
+// addTree adds the tasks to the table, each subtask indented under its parent.
+// Subtasks whose parent is not among the tasks are shown at the top level.
+func addTree(table *taskTable, tasks []*Task) {
+	listed := map[identifier]bool{}
+	for _, task := range tasks {
+		listed[task.GetID()] = true
+	}
+	children := map[identifier][]*Task{}
+	var roots []*Task
+	for _, task := range tasks {
+		if task.parent != "" && listed[task.parent] {
+			children[task.parent] = append(children[task.parent], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+	var add func(task *Task, depth int)
+	add = func(task *Task, depth int) {
+		table.AddIndented(task, depth)
+		for _, child := range children[task.GetID()] {
+			add(child, depth+1)
+		}
+	}
+	for _, task := range roots {
+		add(task, 0)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+)
+
+// maxSuggestionDistance bounds how far what was typed may be from a suggestion.
+const maxSuggestionDistance = 2
+
+// closest returns the candidate nearest to word, when it is near enough to be what was
+// meant; the first candidate wins ties.
+func closest(word string, candidates []string) (string, bool) {
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		if distance := editDistance(word, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, best != "" && bestDistance < len([]rune(word))
+}
+
+// suggestCommand proposes a known command close to a mistyped one.
+func suggestCommand(command string) string {
+	if suggestion, ok := closest(command, commandNames()); ok {
+		return fmt.Sprintf(" Did you mean \"%s\"?", suggestion)
+	}
+	return ""
+}
+
+// suggestTask proposes an existing task whose ID is close to a mistyped one.
+func (l *TaskList) suggestTask(id identifier) string {
+	var ids []string
+	byID := map[string]*Task{}
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			ids = append(ids, string(task.GetID()))
+			byID[string(task.GetID())] = task
+		}
+	}
+	if suggestion, ok := closest(string(id), ids); ok {
+		return fmt.Sprintf(" Did you mean %s \"%s\"?", suggestion, byID[suggestion].GetDescription())
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	tagUsage   = "could not execute tag. Usage: tag <task ID> <label>..."
+	untagUsage = "could not execute untag. Usage: untag <task ID> <label>..."
+	// tagPrefix introduces a tag in show and in queries, e.g. "tag:urgent".
+	tagPrefix = "tag:"
+)
+
+// parseTags returns the labels in lower case, when all are made of letters, digits, - and _.
+func parseTags(labels []string) ([]string, bool) {
+	tags := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label == "" {
+			return nil, false
+		}
+		for _, r := range label {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+				return nil, false
+			}
+		}
+		tags = append(tags, strings.ToLower(label))
+	}
+	return tags, true
+}
+
+// tag adds free-form labels to a task, or removes them when untagging.
+func (l *TaskList) tag(args []string, add bool) error {
+	usage := tagUsage
+	if !add {
+		usage = untagUsage
+	}
+	if len(args) < 2 {
+		return fmt.Errorf(usage)
+	}
+	tags, ok := parseTags(args[1:])
+	if !ok {
+		return fmt.Errorf(usage)
+	}
+	task, err := l.getTaskBy(args[0])
+	if err != nil {
+		return nil
+	}
+	task.SetTags(tags, add)
+	return nil
+}
+
+// SetTags adds the labels to the task, or removes them when add is false.
+func (t *Task) SetTags(tags []string, add bool) {
+	if t.tags == nil {
+		t.tags = map[string]bool{}
+	}
+	for _, tag := range tags {
+		if add {
+			t.tags[tag] = true
+		} else {
+			delete(t.tags, tag)
+		}
+	}
+}
+
+// HasTag returns whether the task carries the given label.
+func (t *Task) HasTag(tag string) bool {
+	return t.tags[strings.ToLower(tag)]
+}
+
+// GetTags returns the labels of the task, sorted alphabetically.
+func (t *Task) GetTags() []string {
+	tags := make([]string, 0, len(t.tags))
+	for tag := range t.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// renderTags returns the labels of a task, each prefixed with #.
+func renderTags(task *Task, _ renderOptions) string {
+	tags := task.GetTags()
+	for i, tag := range tags {
+		tags[i] = "#" + tag
+	}
+	return strings.Join(tags, " ")
+}
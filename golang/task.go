@@ -1,17 +1,42 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
+const (
+	deadlineLayout = "20060102"
+	clockLayout    = "15:04"
+)
+
+// timedDeadlineLayouts are the accepted forms of a deadline at a time of day.
+var timedDeadlineLayouts = []string{"2006-01-02T15:04", "20060102T15:04"}
+
 type deadline struct {
 	value int64
 	date  string
+	// clock is the time of day the task is due at, as HH:MM, empty when due by the end of the day.
+	clock string
 }
 
+// NewDeadline parses a deadline written as YYYYMMDD, or at a time of day as
+// YYYY-MM-DDTHH:MM or YYYYMMDDTHH:MM.
 func NewDeadline(deadlineString string) (deadline, error) {
+	if strings.Contains(deadlineString, "T") {
+		for _, layout := range timedDeadlineLayouts {
+			if due, err := time.ParseInLocation(layout, deadlineString, time.Local); err == nil {
+				d, err := NewDeadline(due.Format(deadlineLayout))
+				d.clock = due.Format(clockLayout)
+				return d, err
+			}
+		}
+		return deadline{}, fmt.Errorf("invalid deadline %q", deadlineString)
+	}
 	value, err := strconv.ParseInt(deadlineString, 10, 64)
 	return deadline{
 		value: value,
@@ -20,6 +45,9 @@ func NewDeadline(deadlineString string) (deadline, error) {
 }
 
 func (d *deadline) String() string {
+	if d.clock != "" {
+		return fmt.Sprintf(" (%v %s)", d.value, d.clock)
+	}
 	return fmt.Sprintf(" (%v)", d.value)
 }
 
@@ -30,14 +58,44 @@ func (d *deadline) IsEmpty() bool {
 	return false
 }
 
-type identifier int64
+// Date returns the calendar day of the deadline, when it is written as YYYYMMDD.
+func (d *deadline) Date() (time.Time, bool) {
+	if d.date == "" {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation(deadlineLayout, d.date, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// Due returns the time the deadline passes: its time of day, or the end of its day.
+func (d *deadline) Due() (time.Time, bool) {
+	date, ok := d.Date()
+	if !ok {
+		return time.Time{}, false
+	}
+	if clock, err := time.Parse(clockLayout, d.clock); err == nil {
+		return date.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute), true
+	}
+	return date.AddDate(0, 0, 1), true
+}
+
+// identifier names a task: a number given in sequence, or a word chosen by the user.
+type identifier string
 
+// NewIdentifier validates an identifier, which may only hold letters, digits, dashes and underscores.
 func NewIdentifier(idString string) (identifier, error) {
-	id, err := strconv.ParseInt(idString, 10, 64)
-	if err != nil {
-		return -1, err
+	if idString == "" {
+		return "", errors.New("empty identifier")
+	}
+	for _, r := range idString {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", fmt.Errorf("invalid character %q in identifier", r)
+		}
 	}
-	return identifier(id), nil
+	return identifier(idString), nil
 }
 
 // Task describes an elementary task.
@@ -46,14 +104,46 @@ type Task struct {
 	description string
 	done        bool
 	deadline    deadline
+	created     time.Time
+	completed   time.Time
+	rollovers   int
+	contexts    map[string]bool
+	tags        map[string]bool
+	size        size
+	priority    priority
+	// parent is the ID of the task this one is a subtask of, empty for top-level tasks.
+	parent identifier
+	notes  []taskNote
+	// blockers are the tasks to be done before this one.
+	blockers []*Task
+	// recurrence is how often the task comes back once checked, empty when it does not.
+	recurrence frequency
+	// goal is the ID of the goal the task contributes to, 0 when there is none.
+	goal int
+	// previousDeadlines holds the deadlines replaced so far, oldest first.
+	previousDeadlines []deadline
+	// remindBefore is how long before its deadline the task is reminded of, 0 for never;
+	// reminded is set once the reminder for the current deadline was given.
+	remindBefore time.Duration
+	reminded     bool
+	// spent is the time tracked on the task between start and stop, or during pomodoros.
+	spent     time.Duration
+	pomodoros int
+	// estimate is how long the task is expected to take, 0 when not estimated.
+	estimate time.Duration
+	// percent is the share of a long-running task already done, set with progress.
+	percent int
+	// assignee is the person owning the task on a shared list, empty when unassigned.
+	assignee string
 }
 
-// NewTask initializes a Task with the given ID, description and completion status.
-func NewTask(id int64, description string, done bool) *Task {
+// NewTask initializes a Task with the given ID, description, completion status and creation time.
+func NewTask(id identifier, description string, done bool, created time.Time) *Task {
 	return &Task{
-		id:          identifier(id),
+		id:          id,
 		description: description,
 		done:        done,
+		created:     created,
 	}
 }
 
@@ -67,18 +157,50 @@ func (t *Task) GetDescription() string {
 	return t.description
 }
 
+// GetCreated returns the time the task was added.
+func (t *Task) GetCreated() time.Time {
+	return t.created
+}
+
 // IsDone returns whether the task is taskDone or not.
 func (t *Task) IsDone() bool {
 	return t.done
 }
 
-// SetDone changes the completion status of the task.
-func (t *Task) SetDone(done bool) {
+// SetDone changes the completion status of the task, recording when it was completed.
+func (t *Task) SetDone(done bool, at time.Time) {
 	t.done = done
+	t.completed = time.Time{}
+	if done {
+		t.completed = at
+	}
+}
+
+// GetCompleted returns the time the task was checked, the zero time when it is not done.
+func (t *Task) GetCompleted() time.Time {
+	return t.completed
 }
 
+// SetDeadline changes the deadline of the task, keeping the replaced one in its history.
 func (t *Task) SetDeadline(d deadline) {
+	if !t.deadline.IsEmpty() && t.deadline != d {
+		t.previousDeadlines = append(t.previousDeadlines, t.deadline)
+	}
 	t.deadline = d
+	t.reminded = false
+}
+
+// WasDueOn returns whether the current deadline, or one it replaced, falls on date.
+func (t *Task) WasDueOn(date time.Time) bool {
+	if t.IsDueOn(date) {
+		return true
+	}
+	for _, previous := range t.previousDeadlines {
+		if due, ok := previous.Date(); ok && startOfDay(due).Equal(startOfDay(date)) {
+			return true
+		}
+	}
+	return false
 }
 
 func (t *Task) GetDeadline() string {
@@ -89,8 +211,20 @@ func (t *Task) GetDeadline() string {
 	return t.deadline.String()
 }
 
-func (t *Task) IsPreviousToCurrentDate() bool {
-	return t.IsPreviousTo(time.Now().Year(), int(time.Now().Month()), time.Now().Day())
+// IsDueOn returns whether the task deadline falls on the same day as date.
+func (t *Task) IsDueOn(date time.Time) bool {
+	due, ok := t.deadline.Date()
+	if !ok {
+		return false
+	}
+	y1, m1, d1 := due.Date()
+	y2, m2, d2 := date.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// IsPreviousToDate returns whether the task is due on or before the day of date.
+func (t *Task) IsPreviousToDate(date time.Time) bool {
+	return t.IsPreviousTo(date.Year(), int(date.Month()), date.Day())
 }
 
 func (t *Task) IsPreviousTo(year, month, day int) bool {
@@ -1,8 +1,11 @@
 package main
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
-func TestIsPreviousToCurrentDate(t *testing.T) {
+func TestIsPreviousToDate(t *testing.T) {
 	//
 	type tt struct {
 		name string
@@ -33,7 +36,7 @@ func TestIsPreviousToCurrentDate(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := tc.task.IsPreviousToCurrentDate()
+			got := tc.task.IsPreviousToDate(time.Now())
 			if tc.want != got {
 				t.Fatalf("expected: %v, got: %v", tc.want, got)
 			}
@@ -63,7 +66,7 @@ func TestTask_IsPreviousTo(t1 *testing.T) {
 		{
 			name: "should return true as task deadline is previous to specified date",
 			taskFields: taskFields{
-				id:          0,
+				id:          "0",
 				description: "",
 				taskDone:    false,
 				deadline: deadline{
@@ -81,7 +84,7 @@ func TestTask_IsPreviousTo(t1 *testing.T) {
 		{
 			name: "should return false as task deadline is not previous to specified date",
 			taskFields: taskFields{
-				id:          0,
+				id:          "0",
 				description: "",
 				taskDone:    false,
 				deadline: deadline{
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// usageReport counts the commands run and how many of them failed, by command name only:
+// neither arguments nor task contents are ever recorded. It is only filled in when the
+// user opts in with the telemetry setting.
+type usageReport struct {
+	Commands map[string]int `json:"commands"`
+	Failures map[string]int `json:"failures"`
+	// failedCode is the code of the failure reported by the command being run, if any.
+	failedCode string
+}
+
+// record counts a run of command, named "other" when it is not a known command.
+func (r *usageReport) record(command string, failed bool) {
+	if r.failedCode == codeUnknownCommand {
+		command = "other"
+	}
+	if r.Commands == nil {
+		r.Commands, r.Failures = map[string]int{}, map[string]int{}
+	}
+	r.Commands[command]++
+	if failed || r.failedCode != "" {
+		r.Failures[command]++
+	}
+}
+
+// telemetry shows the report that would be sent, so users can check what they share.
+func (l *TaskList) telemetry(args []string) error {
+	if len(args) != 1 || args[0] != "show" {
+		return fmt.Errorf("could not execute telemetry. Usage: telemetry show")
+	}
+	if !l.settings.telemetry {
+		fmt.Fprintln(l.out, "Telemetry is off, nothing is recorded. Turn it on with: set telemetry on")
+		return nil
+	}
+	report, err := json.MarshalIndent(l.usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(l.out, string(report))
+	return nil
+}
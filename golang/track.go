@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// startTracking starts counting the time spent on a task, stopping the task tracked before.
+func (l *TaskList) startTracking(idString string) {
+	task, err := l.getTaskBy(idString)
+	if err != nil {
+		return
+	}
+	if l.tracked == task {
+		fmt.Fprintf(l.out, "Task %s is already started.\n", task.GetID())
+		return
+	}
+	if l.tracked != nil {
+		l.stopTracking()
+	}
+	l.tracked, l.trackedSince = task, l.now()
+	fmt.Fprintf(l.out, "Started task %s: %s\n", task.GetID(), task.GetDescription())
+}
+
+// stopTracking adds the time since start to the time spent on the tracked task.
+func (l *TaskList) stopTracking() {
+	task := l.tracked
+	if task == nil {
+		l.fail(codeNotTracking, "No task is started.")
+		return
+	}
+	elapsed := l.now().Sub(l.trackedSince)
+	task.spent += elapsed
+	l.tracked = nil
+	fmt.Fprintf(l.out, "Stopped task %s after %s, %s spent in total.\n", task.GetID(), formatMinutes(elapsed), formatMinutes(task.spent))
+}
+
+// spentDetail describes the time spent on a task for the detailed display.
+func spentDetail(task *Task) (string, bool) {
+	if task.spent == 0 {
+		return "", false
+	}
+	return "time spent: " + formatMinutes(task.spent), true
+}
+
+// formatMinutes writes a duration to the minute, e.g. "45m" or "1h30m".
+func formatMinutes(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	if d == 0 {
+		return "0m"
+	}
+	text := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
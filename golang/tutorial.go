@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tutorialStep asks the user to type one kind of command, and checks that it had the
+// expected effect on the list before moving on.
+type tutorialStep struct {
+	instruction string
+	commands    []string
+	done        func(l *TaskList) bool
+}
+
+var tutorialSteps = []tutorialStep{
+	{
+		instruction: "Tasks are grouped by project. Create one with: add project <name>",
+		commands:    []string{"add"},
+		done:        func(l *TaskList) bool { return len(l.projectTasks) > 0 },
+	},
+	{
+		instruction: "Add a task to your project with: add task <project name> <description>",
+		commands:    []string{"add"},
+		done:        func(l *TaskList) bool { return l.anyTask(func(*Task) bool { return true }) },
+	},
+	{
+		instruction: "Give the task a deadline with: deadline <task ID> <YYYYMMDD>",
+		commands:    []string{"deadline"},
+		done:        func(l *TaskList) bool { return l.anyTask(func(t *Task) bool { _, ok := t.deadline.Date(); return ok }) },
+	},
+	{
+		instruction: "Mark the task as done with: check <task ID>",
+		commands:    []string{"check"},
+		done:        func(l *TaskList) bool { return l.anyTask((*Task).IsDone) },
+	},
+	{
+		instruction: "Look at your tasks with one of the views: show, today, agenda or view calendar",
+		commands:    []string{"show", "today", "agenda", "view"},
+		done:        func(l *TaskList) bool { return true },
+	},
+}
+
+// accepts returns whether the step expects the given command.
+func (s tutorialStep) accepts(command string) bool {
+	for _, c := range s.commands {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
+
+// tutorial walks a new user through the main commands, running each command they type
+// and asking again until the step is achieved. Typing quit leaves the tutorial.
+func (l *TaskList) tutorial() {
+	if l.batch {
+		fmt.Fprintln(l.out, "The tutorial needs an interactive session.")
+		return
+	}
+	scanner := l.input()
+	for i, step := range tutorialSteps {
+		fmt.Fprintf(l.out, "Step %d of %d. %s\n", i+1, len(tutorialSteps), step.instruction)
+		for {
+			fmt.Fprint(l.out, "tutorial> ")
+			if !scanner.Scan() {
+				fmt.Fprintln(l.out)
+				return
+			}
+			cmdLine := scanner.Text()
+			if cmdLine == Quit {
+				fmt.Fprintln(l.out, "Tutorial stopped.")
+				return
+			}
+			args, _ := tokenize(cmdLine)
+			if len(args) > 0 {
+				if flat, err := ungroup(args); err == nil {
+					args = flat
+				}
+			}
+			if len(args) == 0 || !step.accepts(args[0]) {
+				fmt.Fprintf(l.out, "Please type a command starting with \"%s\", or %s to leave the tutorial.\n", strings.Join(step.commands, "\" or \""), Quit)
+				continue
+			}
+			if err := l.execute(cmdLine); err != nil {
+				fmt.Fprintln(l.out, err)
+				continue
+			}
+			if step.done(l) {
+				break
+			}
+			fmt.Fprintln(l.out, "Not quite, try again.")
+		}
+	}
+	fmt.Fprintln(l.out, "Well done! Type help to see every command.")
+}
+
+// anyTask returns whether a task of any project satisfies the predicate.
+func (l *TaskList) anyTask(predicate func(task *Task) bool) bool {
+	for _, tasks := range l.projectTasks {
+		for _, task := range tasks {
+			if predicate(task) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// undoableCommands are the commands the undo command can revert.
+var undoableCommands = map[string]bool{
+	"add": true, "check": true, "uncheck": true, "delete": true, "deadline": true, "clear": true, "rename": true,
+}
+
+// undoLimit is the number of commands kept in the undo history, older ones are forgotten.
+const undoLimit = 100
+
+// undoStep holds the operations of a command: their inverses, run in reverse order to
+// revert it, and the operations themselves, run in order to apply it again.
+type undoStep struct {
+	command string
+	// currentProject is the project most recently added to, before and after the command.
+	currentProject, nextProject string
+	reverts, reapplies          []func()
+}
+
+// beginUndo starts recording the operations of a command.
+func (l *TaskList) beginUndo() {
+	l.pendingUndo = &undoStep{currentProject: l.currentProject}
+}
+
+// endUndo keeps the operations recorded for an undoable command that changed the list.
+// Such a command starts a new history, so what was undone before can no longer be redone.
+func (l *TaskList) endUndo(args []string) {
+	step := l.pendingUndo
+	l.pendingUndo = nil
+	if step == nil || len(step.reverts) == 0 || !undoableCommands[args[0]] {
+		return
+	}
+	step.command = strings.Join(args, " ")
+	step.nextProject = l.currentProject
+	l.undoStack = append(l.undoStack, step)
+	if len(l.undoStack) > undoLimit {
+		l.undoStack = l.undoStack[len(l.undoStack)-undoLimit:]
+	}
+	l.redoStack = nil
+}
+
+// recordUndo adds an operation of the command being run, with its inverse.
+func (l *TaskList) recordUndo(revert, reapply func()) {
+	if l.pendingUndo != nil {
+		l.pendingUndo.reverts = append(l.pendingUndo.reverts, revert)
+		l.pendingUndo.reapplies = append(l.pendingUndo.reapplies, reapply)
+	}
+}
+
+// undo reverts the most recent add, check, uncheck, delete, deadline, clear or rename command.
+func (l *TaskList) undo() {
+	if len(l.undoStack) == 0 {
+		l.fail(codeNothingToUndo, "Nothing to undo.")
+		return
+	}
+	step := l.undoStack[len(l.undoStack)-1]
+	l.undoStack = l.undoStack[:len(l.undoStack)-1]
+	for i := len(step.reverts) - 1; i >= 0; i-- {
+		step.reverts[i]()
+	}
+	l.currentProject = step.currentProject
+	l.redoStack = append(l.redoStack, step)
+	fmt.Fprintf(l.out, "Undid \"%s\".\n", step.command)
+}
+
+// redo applies again the command most recently undone.
+func (l *TaskList) redo() {
+	if len(l.redoStack) == 0 {
+		l.fail(codeNothingToRedo, "Nothing to redo.")
+		return
+	}
+	step := l.redoStack[len(l.redoStack)-1]
+	l.redoStack = l.redoStack[:len(l.redoStack)-1]
+	for _, reapply := range step.reapplies {
+		reapply()
+	}
+	l.currentProject = step.nextProject
+	l.undoStack = append(l.undoStack, step)
+	fmt.Fprintf(l.out, "Redid \"%s\".\n", step.command)
+}
+
+// atomically runs the steps of a command changing several tasks as a whole: when run
+// returns false, the operations it recorded so far are reverted, so a failure midway
+// leaves the list as it was before the command.
+func (l *TaskList) atomically(run func() bool) bool {
+	if l.pendingUndo == nil {
+		l.beginUndo()
+		defer func() { l.pendingUndo = nil }()
+	}
+	step := l.pendingUndo
+	start := len(step.reverts)
+	if run() {
+		return true
+	}
+	for i := len(step.reverts) - 1; i >= start; i-- {
+		step.reverts[i]()
+	}
+	step.reverts, step.reapplies = step.reverts[:start], step.reapplies[:start]
+	return false
+}
+
+// markDone checks or unchecks a task, remembering its previous state.
+func (l *TaskList) markDone(task *Task, done bool) {
+	wasDone, completed := task.done, task.completed
+	task.SetDone(done, l.now())
+	doneAt := task.completed
+	l.recordUndo(
+		func() { task.done, task.completed = wasDone, completed },
+		func() { task.done, task.completed = done, doneAt },
+	)
+}
+
+// recordDeletion remembers where a task is about to be deleted from, with the
+// subtasks and dependent tasks still referring to it.
+func (l *TaskList) recordDeletion(task *Task) {
+	projectName := l.projectOf(task)
+	index := 0
+	for i, t := range l.projectTasks[projectName] {
+		if t == task {
+			index = i
+		}
+	}
+	var subtasks []*Task
+	blocked := map[*Task]int{}
+	for _, tasks := range l.projectTasks {
+		for _, t := range tasks {
+			if t.parent == task.GetID() {
+				subtasks = append(subtasks, t)
+			}
+			for i, blocker := range t.blockers {
+				if blocker == task {
+					blocked[t] = i
+				}
+			}
+		}
+	}
+	l.recordUndo(func() {
+		tasks := l.projectTasks[projectName]
+		if index > len(tasks) {
+			index = len(tasks)
+		}
+		restored := append(append(append(make([]*Task, 0, len(tasks)+1), tasks[:index]...), task), tasks[index:]...)
+		l.projectTasks[projectName] = restored
+		for _, subtask := range subtasks {
+			subtask.parent = task.GetID()
+		}
+		for t, i := range blocked {
+			if i > len(t.blockers) {
+				i = len(t.blockers)
+			}
+			t.blockers = append(append(append(make([]*Task, 0, len(t.blockers)+1), t.blockers[:i]...), task), t.blockers[i:]...)
+		}
+	}, func() {
+		l.removeTask(task)
+		l.orphanSubtasks(task)
+		l.forgetBlocker(task)
+	})
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// wideRanges lists the code points taking two terminal columns: East Asian wide and
+// fullwidth characters, and the symbols rendered as emoji by default.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F},
+	{0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5},
+	{0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x1F000, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// runeWidth returns the number of terminal columns used by r.
+func runeWidth(r rune) int {
+	if r == 0x200D || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Variation_Selector) {
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// displayWidth returns the number of terminal columns used by text.
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// padRight completes text with spaces up to the given display width.
+func padRight(text string, width int) string {
+	if missing := width - displayWidth(text); missing > 0 {
+		return text + strings.Repeat(" ", missing)
+	}
+	return text
+}
+
+// truncate shortens text to at most width terminal columns, marking the cut with an ellipsis.
+// Runes are never split, and combining marks stay attached to the character they modify.
+func truncate(text string, width int) string {
+	if displayWidth(text) <= width {
+		return text
+	}
+	const ellipsis = "..."
+	var kept strings.Builder
+	used := 0
+	for _, r := range text {
+		w := runeWidth(r)
+		if w > 0 && used+w > width-len(ellipsis) {
+			break
+		}
+		kept.WriteRune(r)
+		used += w
+	}
+	return kept.String() + ellipsis
+}
+
+// errUnclosedQuote is returned for a command line opening a double quote it never closes.
+var errUnclosedQuote = errors.New(`could not read the command: a double quote is not closed, type \" for a literal quote`)
+
+// tokenize splits a command line on any Unicode white space. Double quotes group
+// words into a single token, keeping their inner spaces; a backslash keeps the double
+// quote or backslash following it literally.
+func tokenize(line string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	inToken, quoted, escaped := false, false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			if r != '"' && r != '\\' {
+				token.WriteRune('\\')
+			}
+			token.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case r == '"':
+			quoted = !quoted
+			inToken = true
+		case unicode.IsSpace(r) && !quoted:
+			if inToken {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		default:
+			token.WriteRune(r)
+			inToken = true
+		}
+	}
+	if escaped {
+		token.WriteRune('\\')
+	}
+	if quoted {
+		return nil, errUnclosedQuote
+	}
+	if inToken {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
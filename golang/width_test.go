@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "ascii", text: "Buy milk", want: 8},
+		{name: "accented letters", text: "Café crème", want: 10},
+		{name: "combining accent", text: "Café", want: 4},
+		{name: "cjk", text: "牛乳を買う", want: 10},
+		{name: "emoji", text: "🔥 fix", want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.text); got != tt.want {
+				t.Errorf("displayWidth(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{name: "short text is kept", text: "Buy milk", width: 10, want: "Buy milk"},
+		{name: "ascii", text: "Destroy all humans.", width: 10, want: "Destroy..."},
+		{name: "wide runes are not split", text: "牛乳を買う", width: 8, want: "牛乳..."},
+		{name: "combining marks stay attached", text: "Café au lait", width: 7, want: "Café..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate(tt.text, tt.width); got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{name: "spaces", line: "add task  secrets Eat", want: []string{"add", "task", "secrets", "Eat"}},
+		{name: "ideographic space", line: "add　project 買い物", want: []string{"add", "project", "買い物"}},
+		{name: "quoted words", line: `set prompt "{{.Project}}> "`, want: []string{"set", "prompt", "{{.Project}}> "}},
+		{name: "empty line", line: "   ", want: nil},
+		{name: "escaped quotes", line: `add task p Read \"Dune\"`, want: []string{"add", "task", "p", "Read", `"Dune"`}},
+		{name: "escaped quote in quotes", line: `edit 1 "Read \"Dune\"  twice"`, want: []string{"edit", "1", `Read "Dune"  twice`}},
+		{name: "other backslashes", line: `import C:\tasks.csv \\`, want: []string{"import", `C:\tasks.csv`, `\`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenize(tt.line)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %q, %v, want %q", tt.line, got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := tokenize(`add task p "Read Dune`); err != errUnclosedQuote {
+		t.Errorf("an unclosed quote should fail, got %v", err)
+	}
+}
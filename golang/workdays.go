@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdaysByName = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// relativeOffset matches the deadlines counted from today in days, weeks or
+// working days, e.g. "+3d", "+2w" or "+3bd".
+var relativeOffset = regexp.MustCompile(`^\+(\d+)(d|w|bd)$`)
+
+// workCalendar tells working days apart from weekend days and holidays.
+type workCalendar struct {
+	weekend      map[time.Weekday]bool
+	holidays     map[string]bool
+	holidaysFile string
+}
+
+func defaultWorkCalendar() workCalendar {
+	return workCalendar{
+		weekend:  map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		holidays: map[string]bool{},
+	}
+}
+
+// IsWorkingDay returns whether day is neither a weekend day nor a holiday.
+func (c workCalendar) IsWorkingDay(day time.Time) bool {
+	return !c.weekend[day.Weekday()] && !c.holidays[day.Format(deadlineLayout)]
+}
+
+// AddBusinessDays returns the day coming n working days after from.
+func (c workCalendar) AddBusinessDays(from time.Time, n int) time.Time {
+	day := startOfDay(from)
+	for n > 0 {
+		day = day.AddDate(0, 0, 1)
+		if c.IsWorkingDay(day) {
+			n--
+		}
+	}
+	return day
+}
+
+// BusinessDaysBetween counts the working days after from, up to and including to.
+func (c workCalendar) BusinessDaysBetween(from, to time.Time) int {
+	count := 0
+	for day := startOfDay(from).AddDate(0, 0, 1); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+// WeekendString returns the weekend days as a comma-separated list, e.g. "sat,sun".
+func (c workCalendar) WeekendString() string {
+	var days []string
+	for name, weekday := range weekdaysByName {
+		if c.weekend[weekday] {
+			days = append(days, name)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return mondayOffset(weekdaysByName[days[i]]) < mondayOffset(weekdaysByName[days[j]])
+	})
+	if len(days) == 0 {
+		return "none"
+	}
+	return strings.Join(days, ",")
+}
+
+func (c *workCalendar) SetWeekend(value string) error {
+	weekend := map[time.Weekday]bool{}
+	if value != "none" {
+		for _, name := range strings.Split(value, ",") {
+			weekday, ok := weekdaysByName[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return fmt.Errorf("invalid weekend day %q, expected mon, tue, wed, thu, fri, sat or sun", name)
+			}
+			weekend[weekday] = true
+		}
+	}
+	if len(weekend) == 7 {
+		return fmt.Errorf("invalid weekend %q, at least one working day is needed", value)
+	}
+	c.weekend = weekend
+	return nil
+}
+
+// LoadHolidays reads the holiday file at path: one YYYYMMDD or YYYY-MM-DD date per line,
+// blank lines and lines starting with # being ignored.
+func (c *workCalendar) LoadHolidays(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	holidays := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		date := strings.ReplaceAll(line, "-", "")
+		if _, err := time.Parse(deadlineLayout, date); err != nil {
+			return fmt.Errorf("%s line %d: invalid date %q", path, lineNumber, line)
+		}
+		holidays[date] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	c.holidays, c.holidaysFile = holidays, path
+	return nil
+}
+
+// resolveDeadline turns relative deadlines, as offsets or in words, into a YYYYMMDD
+// date counted from today; any other deadline is returned unchanged.
+func (c workCalendar) resolveDeadline(value string, today time.Time) string {
+	if match := relativeOffset.FindStringSubmatch(value); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err == nil {
+			switch match[2] {
+			case "d":
+				return today.AddDate(0, 0, n).Format(deadlineLayout)
+			case "w":
+				return today.AddDate(0, 0, 7*n).Format(deadlineLayout)
+			}
+			return c.AddBusinessDays(today, n).Format(deadlineLayout)
+		}
+	}
+	if due, ok := parseNaturalDate(value, today); ok {
+		return due.Format(deadlineLayout)
+	}
+	return value
+}
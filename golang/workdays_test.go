@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWorkCalendar_AddBusinessDays(t *testing.T) {
+	calendar := defaultWorkCalendar()
+	holidays := filepath.Join(t.TempDir(), "holidays.txt")
+	if err := os.WriteFile(holidays, []byte("# national holidays\n2024-06-12\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := calendar.LoadHolidays(holidays); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	friday := time.Date(2024, time.June, 7, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		days int
+		want time.Time
+	}{
+		{name: "skips the weekend", days: 1, want: time.Date(2024, time.June, 10, 0, 0, 0, 0, time.Local)},
+		{name: "skips holidays", days: 3, want: time.Date(2024, time.June, 13, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.AddBusinessDays(friday, tt.days); !got.Equal(tt.want) {
+				t.Errorf("AddBusinessDays(%d) = %v, want %v", tt.days, got, tt.want)
+			}
+		})
+	}
+
+	if got := calendar.BusinessDaysBetween(friday, time.Date(2024, time.June, 14, 0, 0, 0, 0, time.Local)); got != 4 {
+		t.Errorf("BusinessDaysBetween() = %d, want 4", got)
+	}
+}
+
+func TestDeadlineInBusinessDays(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 7, 15, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"set weekend fri,sat",
+		"deadline 1 +2bd",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"secrets (0/1, 0%)",
+		"    [ ] 1: (20240610) Eat more donuts.",
+		"",
+	)
+}
+
+func TestRelativeDeadlines(t *testing.T) {
+	l, out := newTestTaskList(time.Date(2024, time.June, 7, 15, 0, 0, 0, time.Local))
+	executeAll(t, l,
+		"add project secrets",
+		"add task secrets Eat more donuts.",
+		"add task secrets Destroy all humans.",
+		"deadline 1 +3d",
+		"deadline 2 +2w",
+	)
+	out.Reset()
+
+	executeAll(t, l, "show")
+	assertOutput(t, out,
+		"secrets (0/2, 0%)",
+		"    [ ] 1: (20240610) Eat more donuts.",
+		"    [ ] 2: (20240621) Destroy all humans.",
+		"",
+	)
+}
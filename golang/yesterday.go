@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+)
+
+// yesterday reviews the previous day: the tasks completed during it, and the tasks
+// that were due on it but were not done in time, whatever their deadline is now.
+func (l *TaskList) yesterday() {
+	today := startOfDay(l.now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	var completed, slipped []taskRef
+	for _, project := range l.sortedProjects() {
+		for _, task := range l.projectTasks[project] {
+			ref := taskRef{project: project, task: task}
+			doneAt := task.GetCompleted()
+			if task.IsDone() && !doneAt.Before(yesterday) && doneAt.Before(today) {
+				completed = append(completed, ref)
+				continue
+			}
+			if task.WasDueOn(yesterday) && (!task.IsDone() || !doneAt.Before(today)) {
+				slipped = append(slipped, ref)
+			}
+		}
+	}
+
+	if len(completed)+len(slipped) == 0 {
+		fmt.Fprintln(l.out, "Nothing was completed or due yesterday.")
+		return
+	}
+	l.printAgendaSection("Completed yesterday", completed)
+	l.printAgendaSection("Slipped from yesterday", slipped)
+}